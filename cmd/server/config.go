@@ -6,7 +6,11 @@ package main
 
 import (
 	watchman "github.com/moov-io/watchman"
+	"github.com/moov-io/watchman/internal/changes"
 	"github.com/moov-io/watchman/internal/download"
+	"github.com/moov-io/watchman/internal/jobs"
+	"github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/internal/snapshot"
 
 	"github.com/moov-io/base/config"
 	"github.com/moov-io/base/log"
@@ -17,7 +21,11 @@ type GlobalConfig struct {
 }
 
 type Config struct {
-	Download download.Config
+	Download  download.Config
+	Search    search.Config
+	Jobs      jobs.Config
+	Changes   changes.Config
+	Snapshots snapshot.Config
 
 	Servers ServerConfig
 }
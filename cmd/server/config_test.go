@@ -21,4 +21,5 @@ func TestLoadConfig(t *testing.T) {
 
 	require.Equal(t, ":8084", conf.Servers.BindAddress)
 	require.Equal(t, 12*time.Hour, conf.Download.RefreshInterval)
+	require.Equal(t, 30, conf.Changes.MaxRefreshes)
 }
@@ -7,27 +7,43 @@ package main
 import (
 	"cmp"
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/moov-io/watchman/internal/changes"
 	"github.com/moov-io/watchman/internal/download"
 	"github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/internal/snapshot"
 
 	"github.com/moov-io/base/log"
 )
 
-func setupPeriodicRefreshing(ctx context.Context, logger log.Logger, errs chan error, conf download.Config, downloader download.Downloader, searchService search.Service) error {
-	err := refreshAllSources(ctx, logger, downloader, searchService)
-	if err != nil {
-		return err
+func setupPeriodicRefreshing(ctx context.Context, logger log.Logger, errs chan error, conf Config, downloader download.Downloader, searchService search.Service, changeLog changes.Log, snapshots *snapshot.Store) error {
+	// Serve the last snapshot right away and refresh in the background,
+	// otherwise block until the first refresh completes.
+	loaded := loadSnapshot(logger, snapshots, downloader, searchService, changeLog)
+	if !loaded {
+		err := refreshAllSources(ctx, logger, downloader, searchService, changeLog, snapshots)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Setup periodic refreshing
-	ticker := time.NewTicker(getRefreshInterval(conf))
-	defer ticker.Stop()
+	ticker := time.NewTicker(getRefreshInterval(conf.Download))
 
 	go func() {
+		defer ticker.Stop()
+
+		if loaded {
+			err := refreshAllSources(ctx, logger, downloader, searchService, changeLog, snapshots)
+			if err != nil {
+				logger.Error().LogErrorf("problem refreshing after loading snapshot: %v", err)
+			}
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
@@ -35,9 +51,10 @@ func setupPeriodicRefreshing(ctx context.Context, logger log.Logger, errs chan e
 				return
 
 			case <-ticker.C:
-				err := refreshAllSources(ctx, logger, downloader, searchService)
+				// Keep serving the current entities when a scheduled refresh fails
+				err := refreshAllSources(ctx, logger, downloader, searchService, changeLog, snapshots)
 				if err != nil {
-					errs <- err
+					logger.Error().LogErrorf("problem with scheduled refresh: %v", err)
 				}
 			}
 		}
@@ -46,22 +63,56 @@ func setupPeriodicRefreshing(ctx context.Context, logger log.Logger, errs chan e
 	return nil
 }
 
+// loadSnapshot reads the snapshot saved by a previous refresh, if one exists, into the search service.
+// Each list keeps the snapshot's entities until it's refreshed successfully.
+func loadSnapshot(logger log.Logger, snapshots *snapshot.Store, downloader download.Downloader, searchService search.Service, changeLog changes.Log) bool {
+	if !snapshots.Enabled() {
+		return false
+	}
+
+	snap, err := snapshots.Latest()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn().LogErrorf("problem loading snapshot: %v", err)
+		}
+		return false
+	}
+	logger.Info().Logf("loaded snapshot from %v with %d entities", snap.CreatedAt.Format(time.RFC3339), len(snap.Entities))
+
+	searchService.UpdateEntities(snap.Entities)
+	downloader.Restore(snap.CreatedAt, snap.Entities)
+
+	// The snapshot is our baseline for the next refresh's changes
+	if _, err := changeLog.Record(snap.CreatedAt, snap.Entities); err != nil {
+		logger.Warn().LogErrorf("problem recording snapshot changes: %v", err)
+	}
+
+	return true
+}
+
 const (
 	defaultRefreshInterval = 12 * time.Hour
 )
 
 func getRefreshInterval(conf download.Config) time.Duration {
+	interval := cmp.Or(conf.RefreshInterval, defaultRefreshInterval)
+
 	override := strings.TrimSpace(os.Getenv("DATA_REFRESH_INTERVAL"))
 	if override != "" {
 		dur, err := time.ParseDuration(override)
 		if err == nil {
-			return dur
+			interval = dur
 		}
 	}
-	return cmp.Or(conf.RefreshInterval, defaultRefreshInterval)
+
+	// Lists with a shorter refresh interval need us to check more often.
+	if listInterval := conf.MinimumRefreshInterval(); listInterval > 0 && listInterval < interval {
+		return listInterval
+	}
+	return interval
 }
 
-func refreshAllSources(ctx context.Context, logger log.Logger, downloader download.Downloader, searchService search.Service) error {
+func refreshAllSources(ctx context.Context, logger log.Logger, downloader download.Downloader, searchService search.Service, changeLog changes.Log, snapshots *snapshot.Store) error {
 	// Initial data load
 	stats, err := downloader.RefreshAll(ctx)
 	if err != nil {
@@ -69,9 +120,31 @@ func refreshAllSources(ctx context.Context, logger log.Logger, downloader downlo
 	}
 	logger.Info().Logf("data refreshed - %v entities from %v lists took %v",
 		len(stats.Entities), len(stats.Lists), stats.EndedAt.Sub(stats.StartedAt))
+	if len(stats.Errors) > 0 {
+		logger.Warn().Logf("%d lists failed to refresh and kept their previous entities", len(stats.Errors))
+	}
 
 	// Replace in-mem entities for search.Service
 	searchService.UpdateEntities(stats.Entities)
 
+	// Record what changed since the previous refresh
+	refresh, err := changeLog.Record(stats.EndedAt, stats.Entities)
+	if err != nil {
+		logger.Warn().LogErrorf("problem recording changes: %v", err)
+	} else if refresh != nil {
+		logger.Info().Logf("entity changes - %d added, %d removed, %d modified",
+			refresh.Added, refresh.Removed, refresh.Modified)
+	}
+
+	// Save the entities for the next startup and searches of past dates
+	err = snapshots.Save(snapshot.Snapshot{
+		CreatedAt: stats.EndedAt,
+		Lists:     stats.Lists,
+		Entities:  stats.Entities,
+	})
+	if err != nil {
+		logger.Warn().LogErrorf("problem writing snapshot: %v", err)
+	}
+
 	return nil
 }
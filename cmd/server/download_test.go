@@ -10,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/moov-io/watchman/internal/changes"
 	"github.com/moov-io/watchman/internal/download"
 	"github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/internal/snapshot"
 
 	"github.com/moov-io/base/log"
 	"github.com/stretchr/testify/require"
@@ -28,6 +30,13 @@ func TestGetRefreshInterval(t *testing.T) {
 
 	got = getRefreshInterval(conf)
 	require.Equal(t, 1*time.Hour, got)
+
+	// a list refreshing more often shortens the interval
+	conf.Lists = map[string]download.ListConfig{
+		"us_ofac": {RefreshInterval: 15 * time.Minute},
+	}
+	got = getRefreshInterval(conf)
+	require.Equal(t, 15*time.Minute, got)
 }
 
 func TestDownloader_setupPeriodicRefreshing(t *testing.T) {
@@ -41,7 +50,7 @@ func TestDownloader_setupPeriodicRefreshing(t *testing.T) {
 	dl, err := download.NewDownloader(logger, conf)
 	require.NoError(t, err)
 
-	searchService := search.NewService(logger)
+	searchService := search.NewService(logger, nil)
 
 	go func() {
 		time.Sleep(500 * time.Millisecond)
@@ -49,9 +58,50 @@ func TestDownloader_setupPeriodicRefreshing(t *testing.T) {
 	}()
 
 	errs := make(chan error, 1)
-	err = setupPeriodicRefreshing(ctx, logger, errs, conf, dl, searchService)
+	changeLog := changes.NewLog(changes.Config{})
+	err = setupPeriodicRefreshing(ctx, logger, errs, Config{Download: conf}, dl, searchService, changeLog, nil)
 	require.NoError(t, err)
 
 	cancelFunc()
 	require.NoError(t, <-errs)
 }
+
+func TestDownloader_setupPeriodicRefreshing_Snapshot(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	logger := log.NewTestLogger()
+	conf := Config{
+		Download: download.Config{
+			InitialDataDirectory: filepath.Join("..", "..", "pkg", "ofac", "testdata"),
+		},
+		Snapshots: snapshot.Config{
+			Directory: t.TempDir(),
+		},
+	}
+
+	// Without a snapshot the initial refresh writes one
+	dl, err := download.NewDownloader(logger, conf.Download)
+	require.NoError(t, err)
+
+	snapshots := snapshot.NewStore(conf.Snapshots)
+	err = refreshAllSources(ctx, logger, dl, search.NewService(logger, nil), changes.NewLog(changes.Config{}), snapshots)
+	require.NoError(t, err)
+
+	snap, err := snapshot.Read(conf.Snapshots.Directory)
+	require.NoError(t, err)
+	require.NotEmpty(t, snap.Entities)
+
+	// Later startups serve the snapshot
+	searchService := search.NewService(logger, nil)
+	changeLog := changes.NewLog(changes.Config{})
+	require.True(t, loadSnapshot(logger, snapshots, dl, searchService, changeLog))
+
+	results, err := searchService.Search(ctx, snap.Entities[0], search.SearchOpts{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, snap.Entities[0].SourceID, results[0].SourceID)
+
+	// An empty directory falls back to downloading
+	require.False(t, loadSnapshot(logger, snapshot.NewStore(snapshot.Config{Directory: t.TempDir()}), dl, searchService, changeLog))
+}
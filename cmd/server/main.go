@@ -16,8 +16,11 @@ import (
 	"time"
 
 	"github.com/moov-io/watchman"
+	"github.com/moov-io/watchman/internal/changes"
 	"github.com/moov-io/watchman/internal/download"
+	"github.com/moov-io/watchman/internal/jobs"
 	"github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/internal/snapshot"
 
 	"github.com/gorilla/mux"
 	"github.com/moov-io/base/admin"
@@ -55,19 +58,38 @@ func main() {
 	errs := make(chan error, 1)
 
 	// Setup search service and endpoints
-	searchService := search.NewService(logger)
-	err = setupPeriodicRefreshing(ctx, logger, errs, config.Download, downloader, searchService)
+	snapshots := snapshot.NewStore(config.Snapshots)
+	searchService := search.NewService(logger, snapshots)
+	changeLog := changes.NewLog(config.Changes)
+	err = setupPeriodicRefreshing(ctx, logger, errs, *config, downloader, searchService, changeLog, snapshots)
 	if err != nil {
 		logger.Fatal().LogErrorf("problem during initial download: %v", err)
 		os.Exit(1)
 	}
 
+	// Run bulk screening jobs in the background
+	jobManager, err := jobs.NewManager(logger, config.Jobs, searchService)
+	if err != nil {
+		logger.Fatal().LogErrorf("problem setting up jobs: %v", err)
+		os.Exit(1)
+	}
+	jobManager.Start(ctx)
+
 	router := mux.NewRouter()
 	addPingRoute(router)
 
-	searchController := search.NewController(logger, searchService)
+	searchController := search.NewController(logger, searchService, config.Search)
 	searchController.AppendRoutes(router)
 
+	changesController := changes.NewController(logger, changeLog)
+	changesController.AppendRoutes(router)
+
+	listsController := download.NewController(logger, downloader)
+	listsController.AppendRoutes(router)
+
+	jobsController := jobs.NewController(logger, jobManager)
+	jobsController.AppendRoutes(router)
+
 	// Start Admin server (with Prometheus metrics)
 	adminServer, err := admin.New(admin.Opts{
 		Addr: config.Servers.AdminAddress,
@@ -76,6 +98,9 @@ func main() {
 		errs <- fmt.Errorf("problem starting admin server: %v", err)
 	} else {
 		adminServer.AddVersionHandler(watchman.Version) // Setup 'GET /version'
+
+		// Setup 'GET /ready' to fail until every list is loaded and fresh
+		adminServer.AddReadinessCheck("lists", downloader.Ready)
 	}
 	go func() {
 		if adminServer == nil {
@@ -0,0 +1,112 @@
+package changes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+)
+
+type Controller interface {
+	AppendRoutes(router *mux.Router) *mux.Router
+}
+
+func NewController(logger log.Logger, changeLog Log) Controller {
+	return &controller{
+		logger:    logger,
+		changeLog: changeLog,
+	}
+}
+
+type controller struct {
+	logger    log.Logger
+	changeLog Log
+}
+
+func (c *controller) AppendRoutes(router *mux.Router) *mux.Router {
+	router.
+		Name("Changes.v2").
+		Methods("GET").
+		Path("/v2/changes").
+		HandlerFunc(c.listChanges)
+
+	return router
+}
+
+type changesResponse struct {
+	Refreshes []Refresh `json:"refreshes"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (c *controller) listChanges(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	since, err := readSince(q.Get("since"))
+	if err != nil {
+		c.logger.Warn().LogErrorf("problem reading changes request: %v", err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	refreshes := c.changeLog.Since(since)
+	if source := strings.TrimSpace(q.Get("source")); source != "" {
+		refreshes = filterSource(refreshes, search.SourceList(source))
+	}
+	if refreshes == nil {
+		refreshes = []Refresh{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changesResponse{
+		Refreshes: refreshes,
+	})
+}
+
+var (
+	allowedSinceFormats = []string{time.RFC3339, "2006-01-02"}
+)
+
+func readSince(input string) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, nil
+	}
+	for _, format := range allowedSinceFormats {
+		tt, err := time.Parse(format, input)
+		if err == nil {
+			return tt, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q, expected RFC3339 timestamp or YYYY-MM-DD", input)
+}
+
+// filterSource keeps the changes from one list, dropping refreshes which had none
+func filterSource(refreshes []Refresh, source search.SourceList) []Refresh {
+	var out []Refresh
+	for _, refresh := range refreshes {
+		var changes []EntityChange
+		for _, change := range refresh.Changes {
+			if change.Source == source {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) > 0 {
+			out = append(out, newRefresh(refresh.RefreshedAt, changes))
+		}
+	}
+	return out
+}
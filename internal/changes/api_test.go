@@ -0,0 +1,62 @@
+package changes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_ListChanges(t *testing.T) {
+	changeLog := NewLog(Config{})
+	refreshedAt := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := changeLog.Record(refreshedAt.Add(-24*time.Hour), nil)
+	require.NoError(t, err)
+
+	eu := testEntity("EU-1", "Jane Doe")
+	eu.Source = search.SourceEUCSL
+	_, err = changeLog.Record(refreshedAt, []search.Entity[search.Value]{testEntity("1", "John Doe"), eu})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	NewController(log.NewTestLogger(), changeLog).AppendRoutes(router)
+
+	get := func(t *testing.T, url string) (int, changesResponse) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+
+		var resp changesResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		}
+		return w.Code, resp
+	}
+
+	code, resp := get(t, "/v2/changes")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Refreshes, 1)
+	require.Equal(t, 2, resp.Refreshes[0].Added)
+
+	code, resp = get(t, "/v2/changes?since=2024-03-01&source=eu_csl")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Refreshes, 1)
+	require.Equal(t, 1, resp.Refreshes[0].Added)
+	require.Equal(t, "EU-1", resp.Refreshes[0].Changes[0].SourceID)
+
+	code, resp = get(t, "/v2/changes?since=2024-03-02T00:00:00Z")
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, resp.Refreshes)
+
+	code, _ = get(t, "/v2/changes?since=yesterday")
+	require.Equal(t, http.StatusBadRequest, code)
+}
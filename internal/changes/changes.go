@@ -0,0 +1,262 @@
+package changes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+type ChangeType string
+
+var (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Refresh holds every entity which changed between two list refreshes
+type Refresh struct {
+	RefreshedAt time.Time `json:"refreshedAt"`
+
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+
+	Changes []EntityChange `json:"changes"`
+}
+
+func newRefresh(refreshedAt time.Time, changes []EntityChange) Refresh {
+	out := Refresh{
+		RefreshedAt: refreshedAt,
+		Changes:     changes,
+	}
+	for _, change := range changes {
+		switch change.Type {
+		case ChangeAdded:
+			out.Added++
+		case ChangeRemoved:
+			out.Removed++
+		case ChangeModified:
+			out.Modified++
+		}
+	}
+	return out
+}
+
+// EntityChange is an entity which was added, removed or modified. Entities are matched on their Source and SourceID.
+type EntityChange struct {
+	Source   search.SourceList `json:"source"`
+	SourceID string            `json:"sourceID"`
+	Name     string            `json:"name"`
+	Type     ChangeType        `json:"type"`
+
+	// Fields is set for modified entities
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is a field which differs between refreshes. Field is the JSON path of the value (e.g. "person.birthDate").
+type FieldChange struct {
+	Field    string          `json:"field"`
+	Previous json.RawMessage `json:"previous"`
+	Current  json.RawMessage `json:"current"`
+}
+
+// Diff compares the entities from two refreshes. Changes are sorted by Source, SourceID and then the type of change.
+func Diff(previous, current []search.Entity[search.Value]) ([]EntityChange, error) {
+	changes, _, err := diff(previous, nil, current)
+	return changes, err
+}
+
+// diff only compares the fields of entities whose hash differs from the previous refresh. The previous
+// entities are hashed when prevHashes is nil. The current entities' hashes are returned for the next refresh.
+func diff(previous []search.Entity[search.Value], prevHashes map[string]uint64, current []search.Entity[search.Value]) ([]EntityChange, map[string]uint64, error) {
+	prev := keyEntities(previous)
+	curr := keyEntities(current)
+	currHashes := make(map[string]uint64, len(curr))
+
+	var out []EntityChange
+	for key, entity := range curr {
+		hash, err := entityHash(entity)
+		if err != nil {
+			return nil, nil, fmt.Errorf("hashing %s %s: %w", entity.Source, entity.SourceID, err)
+		}
+		currHashes[key] = hash
+
+		before, exists := prev[key]
+		if !exists {
+			out = append(out, newChange(entity, ChangeAdded))
+			continue
+		}
+
+		prevHash, hashed := prevHashes[key]
+		if !hashed {
+			prevHash, err = entityHash(before)
+			if err != nil {
+				return nil, nil, fmt.Errorf("hashing %s %s: %w", before.Source, before.SourceID, err)
+			}
+		}
+		if prevHash == hash {
+			continue
+		}
+
+		fields, err := diffFields(before, entity)
+		if err != nil {
+			return nil, nil, fmt.Errorf("comparing %s %s: %w", entity.Source, entity.SourceID, err)
+		}
+		if len(fields) > 0 {
+			change := newChange(entity, ChangeModified)
+			change.Fields = fields
+			out = append(out, change)
+		}
+	}
+	for key, entity := range prev {
+		if _, exists := curr[key]; !exists {
+			out = append(out, newChange(entity, ChangeRemoved))
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Source != out[j].Source {
+			return out[i].Source < out[j].Source
+		}
+		if out[i].SourceID != out[j].SourceID {
+			return out[i].SourceID < out[j].SourceID
+		}
+		return out[i].Type < out[j].Type
+	})
+
+	return out, currHashes, nil
+}
+
+// entityHash writes the entity's JSON into a hash, which is cheaper than keeping the encoded entity to compare
+func entityHash(entity search.Entity[search.Value]) (uint64, error) {
+	h := fnv.New64a()
+	if err := json.NewEncoder(h).Encode(entity); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+func newChange(entity search.Entity[search.Value], changeType ChangeType) EntityChange {
+	return EntityChange{
+		Source:   entity.Source,
+		SourceID: entity.SourceID,
+		Name:     entity.Name,
+		Type:     changeType,
+	}
+}
+
+// keyEntities indexes entities by Source and SourceID. Some lists publish several records with the
+// same identifier, so repeated keys are numbered in the order they appear.
+func keyEntities(entities []search.Entity[search.Value]) map[string]search.Entity[search.Value] {
+	out := make(map[string]search.Entity[search.Value], len(entities))
+	seen := make(map[string]int)
+	for _, entity := range entities {
+		key := fmt.Sprintf("%s/%s", entity.Source, entity.SourceID)
+		if n := seen[key]; n > 0 {
+			seen[key]++
+			key = fmt.Sprintf("%s#%d", key, n)
+		} else {
+			seen[key] = 1
+		}
+		out[key] = entity
+	}
+	return out
+}
+
+func diffFields(previous, current search.Entity[search.Value]) ([]FieldChange, error) {
+	before, err := json.Marshal(previous)
+	if err != nil {
+		return nil, err
+	}
+	after, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(before, after) {
+		return nil, nil
+	}
+
+	beforeFields, afterFields := flatten(before), flatten(after)
+
+	var out []FieldChange
+	for field, value := range afterFields {
+		prev, exists := beforeFields[field]
+		if !exists && isEmpty(value) {
+			continue
+		}
+		if !exists || !equalValues(prev, value) {
+			out = append(out, FieldChange{
+				Field:    field,
+				Previous: orNull(prev),
+				Current:  value,
+			})
+		}
+	}
+	for field, value := range beforeFields {
+		if _, exists := afterFields[field]; !exists && !isEmpty(value) {
+			out = append(out, FieldChange{
+				Field:    field,
+				Previous: value,
+				Current:  orNull(nil),
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Field < out[j].Field
+	})
+	return out, nil
+}
+
+// flatten returns every value of a JSON object keyed by its path. Arrays are kept as a single value.
+func flatten(data []byte) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage)
+	flattenInto(out, "", data)
+	return out
+}
+
+func flattenInto(out map[string]json.RawMessage, prefix string, data []byte) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		out[prefix] = data // not an object
+		return
+	}
+
+	for key, value := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flattenInto(out, path, value)
+	}
+}
+
+// equalValues compares two JSON values, treating null and empty arrays as the same since
+// entities loaded from a snapshot don't keep the difference.
+func equalValues(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	return isEmpty(a) && isEmpty(b)
+}
+
+func isEmpty(value json.RawMessage) bool {
+	switch string(bytes.TrimSpace(value)) {
+	case "null", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+func orNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+package changes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEntity(sourceID, name string) search.Entity[search.Value] {
+	return search.Entity[search.Value]{
+		Name:     name,
+		Type:     search.EntityPerson,
+		Source:   search.SourceUSOFAC,
+		SourceID: sourceID,
+		Person: &search.Person{
+			Name: name,
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	modified := testEntity("2", "Jane Doe")
+	modified.Person.AltNames = []string{"Janie Doe"}
+	modified.SanctionsInfo = &search.SanctionsInfo{Programs: []string{"SDGT"}}
+
+	previous := []search.Entity[search.Value]{
+		testEntity("1", "John Doe"),
+		testEntity("2", "Jane Doe"),
+		testEntity("3", "Unchanged"),
+	}
+	current := []search.Entity[search.Value]{
+		modified,
+		testEntity("3", "Unchanged"),
+		testEntity("4", "New Person"),
+	}
+
+	changes, err := Diff(previous, current)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	require.Equal(t, "1", changes[0].SourceID)
+	require.Equal(t, ChangeRemoved, changes[0].Type)
+	require.Empty(t, changes[0].Fields)
+
+	require.Equal(t, "2", changes[1].SourceID)
+	require.Equal(t, ChangeModified, changes[1].Type)
+
+	var fields []string
+	for _, field := range changes[1].Fields {
+		fields = append(fields, field.Field)
+	}
+	require.Equal(t, []string{"person.altNames", "sanctionsInfo.description", "sanctionsInfo.programs", "sanctionsInfo.secondary"}, fields)
+	require.Equal(t, json.RawMessage("null"), changes[1].Fields[0].Previous)
+	require.Equal(t, json.RawMessage(`["Janie Doe"]`), changes[1].Fields[0].Current)
+
+	require.Equal(t, "4", changes[2].SourceID)
+	require.Equal(t, ChangeAdded, changes[2].Type)
+	require.Equal(t, "New Person", changes[2].Name)
+}
+
+func TestDiff_EmptyValues(t *testing.T) {
+	previous := testEntity("1", "John Doe")
+	previous.Person.AltNames = []string{}
+
+	current := testEntity("1", "John Doe")
+	current.Person.AltNames = nil
+
+	changes, err := Diff([]search.Entity[search.Value]{previous}, []search.Entity[search.Value]{current})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestDiff_DuplicateSourceIDs(t *testing.T) {
+	entities := []search.Entity[search.Value]{
+		testEntity("1", "John Doe"),
+		testEntity("1", "Johnny Doe"),
+	}
+	changes, err := Diff(entities, entities)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	changes, err = Diff(entities, entities[:1])
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ChangeRemoved, changes[0].Type)
+	require.Equal(t, "Johnny Doe", changes[0].Name)
+}
+
+func TestLog(t *testing.T) {
+	changeLog := NewLog(Config{MaxRefreshes: 2})
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// The first refresh sets the baseline
+	refresh, err := changeLog.Record(start, []search.Entity[search.Value]{testEntity("1", "John Doe")})
+	require.NoError(t, err)
+	require.Nil(t, refresh)
+	require.Empty(t, changeLog.Since(time.Time{}))
+
+	var entities []search.Entity[search.Value]
+	for i, name := range []string{"A", "B", "C"} {
+		entities = append(entities, testEntity(name, name))
+
+		refresh, err := changeLog.Record(start.Add(time.Duration(i+1)*time.Hour), entities)
+		require.NoError(t, err)
+		require.NotNil(t, refresh)
+	}
+
+	// unchanged refreshes are not kept
+	refresh, err = changeLog.Record(start.Add(5*time.Hour), entities)
+	require.NoError(t, err)
+	require.Nil(t, refresh)
+
+	refreshes := changeLog.Since(time.Time{})
+	require.Len(t, refreshes, 2)
+	require.Equal(t, start.Add(2*time.Hour), refreshes[0].RefreshedAt)
+	require.Equal(t, 1, refreshes[0].Added)
+	require.Equal(t, 0, refreshes[0].Removed)
+
+	refreshes = changeLog.Since(start.Add(2 * time.Hour))
+	require.Len(t, refreshes, 1)
+	require.Equal(t, "C", refreshes[0].Changes[0].SourceID)
+}
+
+func TestDiff_Hashes(t *testing.T) {
+	previous := []search.Entity[search.Value]{testEntity("1", "John Doe"), testEntity("2", "Jane Doe")}
+
+	changes, hashes, err := diff(nil, nil, previous)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Len(t, hashes, 2)
+
+	// only entities whose hash changed are compared
+	current := []search.Entity[search.Value]{testEntity("1", "John Doe"), testEntity("2", "Jane Smith")}
+	changes, next, err := diff(previous, hashes, current)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, ChangeModified, changes[0].Type)
+	require.Equal(t, "2", changes[0].SourceID)
+	require.Equal(t, hashes["us_ofac/1"], next["us_ofac/1"])
+	require.NotEqual(t, hashes["us_ofac/2"], next["us_ofac/2"])
+
+	// matching hashes skip the comparison
+	changes, _, err = diff(previous, next, current)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
@@ -0,0 +1,91 @@
+package changes
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+type Config struct {
+	// MaxRefreshes is how many refreshes with changes are kept
+	MaxRefreshes int
+}
+
+const (
+	defaultMaxRefreshes = 30
+)
+
+// Log keeps the changes from recent refreshes
+type Log interface {
+	// Record compares entities against the previous refresh and saves the differences.
+	// The first call only sets the baseline to compare against.
+	Record(refreshedAt time.Time, entities []search.Entity[search.Value]) (*Refresh, error)
+
+	// Since returns each Refresh after the given time, oldest first
+	Since(since time.Time) []Refresh
+}
+
+func NewLog(conf Config) Log {
+	maxRefreshes := conf.MaxRefreshes
+	if maxRefreshes <= 0 {
+		maxRefreshes = defaultMaxRefreshes
+	}
+	return &changeLog{
+		maxRefreshes: maxRefreshes,
+	}
+}
+
+type changeLog struct {
+	maxRefreshes int
+
+	mu        sync.RWMutex
+	baseline  bool
+	previous  []search.Entity[search.Value]
+	hashes    map[string]uint64 // of previous, nil until the first comparison
+	refreshes []Refresh
+}
+
+func (l *changeLog) Record(refreshedAt time.Time, entities []search.Entity[search.Value]) (*Refresh, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.baseline {
+		l.baseline = true
+		l.previous = entities
+		return nil, nil
+	}
+
+	changes, hashes, err := diff(l.previous, l.hashes, entities)
+	if err != nil {
+		return nil, fmt.Errorf("computing changes: %w", err)
+	}
+	l.previous = entities
+	l.hashes = hashes
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	refresh := newRefresh(refreshedAt, changes)
+	l.refreshes = append(l.refreshes, refresh)
+	if extra := len(l.refreshes) - l.maxRefreshes; extra > 0 {
+		l.refreshes = append([]Refresh(nil), l.refreshes[extra:]...)
+	}
+
+	return &refresh, nil
+}
+
+func (l *changeLog) Since(since time.Time) []Refresh {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var out []Refresh
+	for _, refresh := range l.refreshes {
+		if refresh.RefreshedAt.After(since) {
+			out = append(out, refresh)
+		}
+	}
+	return out
+}
@@ -0,0 +1,447 @@
+package clusters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/moov-io/watchman/internal/prepare"
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/pariz/gountries"
+)
+
+// Reasons records are linked into a cluster
+const (
+	ReasonIdentifier    = "identifier"
+	ReasonCryptoAddress = "crypto_address"
+	ReasonNameBirthDate = "name_birth_date"
+	ReasonNameAddress   = "name_address"
+)
+
+var (
+	// maxKeyMembers skips keys shared by more records than this. They're too common
+	// (such as a popular name in a capital city) to say the records are the same party.
+	maxKeyMembers = 10
+
+	// minIdentifierLength skips short identifiers, which are often placeholders or typos
+	minIdentifierLength = 5
+)
+
+// countrySeparator follows an identifier key when the list published the identifier's country
+const countrySeparator = "|country="
+
+// Cluster is a set of records from different lists which describe the same party
+type Cluster struct {
+	// ID is derived from the cluster's first record (by source list and source ID), so it's
+	// the same across refreshes and restarts for as long as that record is in the cluster.
+	ID string `json:"id"`
+
+	// Reasons are how the records were linked, such as sharing an identifier
+	Reasons []string `json:"reasons"`
+
+	Entities []search.Entity[search.Value] `json:"entities"`
+}
+
+// Index holds the clusters found within a set of entities. Entities which aren't
+// linked to a record from another list aren't part of any cluster.
+type Index struct {
+	clusters map[string]*Cluster // by ID
+	byRecord map[string]*Cluster // by source and source ID
+}
+
+// Resolve links records from different lists which share an identifier, crypto address,
+// name and birth date, or name and address. Only entities of the same type are linked.
+func Resolve(entities []search.Entity[search.Value]) *Index {
+	uf := newUnionFind(len(entities))
+
+	// Group records by each key they have, then link records which share a key across lists
+	members := make(map[string][]int)
+	countries := make(map[string]map[int]string) // key -> record -> identifier country
+	for idx, entity := range entities {
+		for _, key := range entityKeys(entity) {
+			key, country, _ := strings.Cut(key, countrySeparator)
+			if idxs := members[key]; len(idxs) == 0 || idxs[len(idxs)-1] != idx {
+				members[key] = append(members[key], idx)
+			}
+			if country != "" {
+				if countries[key] == nil {
+					countries[key] = make(map[int]string)
+				}
+				countries[key][idx] = country
+			}
+		}
+	}
+
+	linkedBy := make(map[int][]string) // record -> reasons
+	for key, found := range members {
+		for _, idxs := range sameCountry(found, countries[key]) {
+			if len(idxs) < 2 || len(idxs) > maxKeyMembers || !multipleSources(entities, idxs) {
+				continue
+			}
+			for _, idx := range idxs[1:] {
+				uf.union(idxs[0], idx)
+			}
+			linkedBy[idxs[0]] = append(linkedBy[idxs[0]], keyReason(key))
+		}
+	}
+
+	// Collect each cluster's members and the reasons they were linked
+	groups := make(map[int][]int)
+	for idx := range entities {
+		root := uf.find(idx)
+		groups[root] = append(groups[root], idx)
+	}
+	reasons := make(map[int]map[string]bool)
+	for idx, found := range linkedBy {
+		root := uf.find(idx)
+		if reasons[root] == nil {
+			reasons[root] = make(map[string]bool)
+		}
+		for _, reason := range found {
+			reasons[root][reason] = true
+		}
+	}
+
+	out := &Index{
+		clusters: make(map[string]*Cluster),
+		byRecord: make(map[string]*Cluster),
+	}
+	for root, idxs := range groups {
+		if len(idxs) < 2 {
+			continue
+		}
+
+		cluster := &Cluster{}
+		for _, idx := range idxs {
+			cluster.Entities = append(cluster.Entities, entities[idx])
+		}
+		sort.SliceStable(cluster.Entities, func(i, j int) bool {
+			return recordKey(cluster.Entities[i]) < recordKey(cluster.Entities[j])
+		})
+		cluster.ID = clusterID(cluster.Entities[0])
+
+		for reason := range reasons[root] {
+			cluster.Reasons = append(cluster.Reasons, reason)
+		}
+		sort.Strings(cluster.Reasons)
+
+		out.clusters[cluster.ID] = cluster
+		for _, entity := range cluster.Entities {
+			if _, exists := out.byRecord[recordKey(entity)]; !exists {
+				out.byRecord[recordKey(entity)] = cluster
+			}
+		}
+	}
+	return out
+}
+
+// Len returns how many clusters were found
+func (idx *Index) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.clusters)
+}
+
+// Get returns the cluster with the given ID
+func (idx *Index) Get(id string) (Cluster, bool) {
+	if idx == nil {
+		return Cluster{}, false
+	}
+	cluster, exists := idx.clusters[id]
+	if !exists {
+		return Cluster{}, false
+	}
+	return *cluster, true
+}
+
+// Lookup returns the cluster which contains the entity
+func (idx *Index) Lookup(entity search.Entity[search.Value]) (Cluster, bool) {
+	if idx == nil {
+		return Cluster{}, false
+	}
+	cluster, exists := idx.byRecord[recordKey(entity)]
+	if !exists {
+		return Cluster{}, false
+	}
+	return *cluster, true
+}
+
+// Linked returns the other records in the entity's cluster
+func (idx *Index) Linked(entity search.Entity[search.Value]) (string, []search.LinkedEntity) {
+	cluster, exists := idx.Lookup(entity)
+	if !exists {
+		return "", nil
+	}
+
+	key := recordKey(entity)
+	var out []search.LinkedEntity
+	for _, member := range cluster.Entities {
+		if recordKey(member) == key {
+			continue
+		}
+		out = append(out, search.LinkedEntity{
+			Name:     member.Name,
+			Type:     member.Type,
+			Source:   member.Source,
+			SourceID: member.SourceID,
+		})
+	}
+	return cluster.ID, out
+}
+
+func recordKey(entity search.Entity[search.Value]) string {
+	return fmt.Sprintf("%s/%s", entity.Source, entity.SourceID)
+}
+
+func clusterID(first search.Entity[search.Value]) string {
+	sum := sha256.Sum256([]byte(recordKey(first)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func multipleSources(entities []search.Entity[search.Value], idxs []int) bool {
+	for _, idx := range idxs[1:] {
+		if entities[idx].Source != entities[idxs[0]].Source {
+			return true
+		}
+	}
+	return false
+}
+
+// sameCountry splits the records sharing an identifier by the country it was issued in. Records
+// without a country are linked to the others only when they all have the same country.
+func sameCountry(idxs []int, countries map[int]string) [][]int {
+	if len(countries) == 0 {
+		return [][]int{idxs}
+	}
+
+	var unknown []int
+	byCountry := make(map[string][]int)
+	for _, idx := range idxs {
+		if country, exists := countries[idx]; exists {
+			byCountry[country] = append(byCountry[country], idx)
+		} else {
+			unknown = append(unknown, idx)
+		}
+	}
+
+	var out [][]int
+	for _, found := range byCountry {
+		if len(byCountry) == 1 {
+			found = append(found, unknown...)
+			sort.Ints(found)
+		}
+		out = append(out, found)
+	}
+	return out
+}
+
+func keyReason(key string) string {
+	reason, _, _ := strings.Cut(key, "|")
+	return reason
+}
+
+// entityKeys returns each value which identifies the entity, prefixed with the reason and entity type
+func entityKeys(entity search.Entity[search.Value]) []string {
+	var out []string
+	add := func(reason string, parts ...string) {
+		for _, part := range parts {
+			if part == "" {
+				return
+			}
+		}
+		out = append(out, reason+"|"+string(entity.Type)+"|"+strings.Join(parts, "|"))
+	}
+	// Identifiers include their kind of document, so the same number on a passport and a tax ID
+	// isn't linked. Their country is used by Resolve when the list publishes it.
+	addIdentifier := func(idType, country, id string) {
+		before := len(out)
+		add(ReasonIdentifier, identifierType(idType), normalizeIdentifier(id))
+		if country = normalizeCountry(country); country != "" && len(out) > before {
+			out[len(out)-1] += countrySeparator + country
+		}
+	}
+
+	var names []string
+	if entity.Name != "" {
+		names = append(names, entity.Name)
+	}
+
+	switch {
+	case entity.Person != nil:
+		names = append(names, entity.Person.AltNames...)
+		for _, id := range entity.Person.GovernmentIDs {
+			addIdentifier(string(id.Type), id.Country, id.Identifier)
+		}
+		if entity.Person.BirthDate != nil {
+			dob := entity.Person.BirthDate.Format("2006-01-02")
+			for _, name := range names {
+				add(ReasonNameBirthDate, normalizeName(name), dob)
+			}
+		}
+
+	case entity.Business != nil:
+		names = append(names, entity.Business.AltNames...)
+		for _, id := range entity.Business.Identifier {
+			addIdentifier(id.Name, id.Country, id.Identifier)
+		}
+
+	case entity.Organization != nil:
+		names = append(names, entity.Organization.AltNames...)
+		for _, id := range entity.Organization.Identifier {
+			addIdentifier(id.Name, id.Country, id.Identifier)
+		}
+
+	case entity.Vessel != nil:
+		add(ReasonIdentifier, "imo", normalizeIdentifier(entity.Vessel.IMONumber))
+		add(ReasonIdentifier, "mmsi", normalizeIdentifier(entity.Vessel.MMSI))
+
+	case entity.Aircraft != nil:
+		add(ReasonIdentifier, "serial", normalizeIdentifier(entity.Aircraft.SerialNumber))
+	}
+
+	for _, crypto := range entity.CryptoAddresses {
+		add(ReasonCryptoAddress, strings.ToUpper(strings.TrimSpace(crypto.Currency)), strings.TrimSpace(crypto.Address))
+	}
+
+	for _, addr := range entity.Addresses {
+		country := strings.ToLower(strings.TrimSpace(addr.Country))
+		city := normalizeName(addr.City)
+		for _, name := range names {
+			add(ReasonNameAddress, normalizeName(name), country, city)
+		}
+	}
+
+	return dedupe(out)
+}
+
+// normalizeName lowercases and removes punctuation, then sorts the words so
+// "MADURO MOROS, Nicolas" and "Nicolas Maduro Moros" are the same.
+func normalizeName(name string) string {
+	words := strings.Fields(prepare.LowerAndRemovePunctuation(name))
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// normalizeIdentifier keeps only the letters and digits of an identifier
+func normalizeIdentifier(id string) string {
+	var sb strings.Builder
+	for _, r := range id {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	if sb.Len() < minIdentifierLength {
+		return ""
+	}
+	return sb.String()
+}
+
+// identifierType maps the many names lists use for identifiers (e.g. "Registration ID" and
+// "Registration Number") into a few kinds of document.
+func identifierType(idType string) string {
+	words := strings.FieldsFunc(strings.ToLower(idType), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	has := func(values ...string) bool {
+		for _, word := range words {
+			for _, v := range values {
+				// short values are abbreviations, which must be the whole word
+				if word == v || (len(v) > 4 && strings.HasPrefix(word, v)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("passport"):
+		return "passport"
+	case has("tax", "vat", "inn", "tin", "cuit", "rfc"):
+		return "tax"
+	case has("swift", "bic"):
+		return "swift"
+	case has("lei"):
+		return "lei"
+	case has("regist", "company", "commercial", "business", "ogrn", "incorporat"):
+		return "registration"
+	case has("national", "cedula", "curp", "ssn", "personal", "identi", "electoral", "citizen"):
+		return "national"
+	}
+	return "other"
+}
+
+var (
+	countriesOnce sync.Once
+	countries     *gountries.Query
+)
+
+// normalizeCountry returns the ISO 3166 alpha-2 code for a country's name or code,
+// or the uppercased value when it isn't recognized.
+func normalizeCountry(country string) string {
+	country = strings.TrimSpace(country)
+	if country == "" {
+		return ""
+	}
+	countriesOnce.Do(func() {
+		countries = gountries.New()
+	})
+
+	found, err := countries.FindCountryByAlpha(country)
+	if err != nil {
+		found, err = countries.FindCountryByName(country)
+	}
+	if err != nil {
+		return strings.ToUpper(country)
+	}
+	return found.Alpha2
+}
+
+func dedupe(keys []string) []string {
+	sort.Strings(keys)
+	out := keys[:0]
+	for i, key := range keys {
+		if i == 0 || key != keys[i-1] {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// union keeps the smaller index as the root so results don't depend on map order
+func (u *unionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return
+	}
+	if rb < ra {
+		ra, rb = rb, ra
+	}
+	u.parent[rb] = ra
+}
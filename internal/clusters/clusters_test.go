@@ -0,0 +1,211 @@
+package clusters
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func person(source search.SourceList, sourceID, name string, dob *time.Time, ids ...string) search.Entity[search.Value] {
+	entity := search.Entity[search.Value]{
+		Name:     name,
+		Type:     search.EntityPerson,
+		Source:   source,
+		SourceID: sourceID,
+		Person: &search.Person{
+			Name:      name,
+			BirthDate: dob,
+		},
+	}
+	for _, id := range ids {
+		entity.Person.GovernmentIDs = append(entity.Person.GovernmentIDs, search.GovernmentID{
+			Type:       search.GovernmentIDPassport,
+			Identifier: id,
+		})
+	}
+	return entity
+}
+
+func TestResolve(t *testing.T) {
+	dob := time.Date(1962, time.November, 23, 0, 0, 0, 0, time.UTC)
+
+	entities := []search.Entity[search.Value]{
+		person(search.SourceUSOFAC, "22790", "MADURO MOROS, Nicolas", &dob, "5892464"),
+		person(search.SourceEUCSL, "13", "Nicolas Maduro Moros", nil, "5892-464"),
+		person(search.SourceUKCSL, "VEN0001", "Nicolas MADURO MOROS", &dob),
+		person(search.SourceUNCSL, "QDi.001", "John Doe", &dob),
+		// records from one list aren't linked to each other
+		person(search.SourceUSOFAC, "1", "Jane Doe", nil, "X1234567"),
+		person(search.SourceUSOFAC, "2", "Jane Doe", nil, "X1234567"),
+		// short identifiers are ignored
+		person(search.SourceEUCSL, "99", "Someone Else", nil, "123"),
+		person(search.SourceUKCSL, "99", "Another Person", nil, "123"),
+	}
+
+	index := Resolve(entities)
+	require.Equal(t, 1, index.Len())
+
+	cluster, found := index.Lookup(entities[0])
+	require.True(t, found)
+	require.Len(t, cluster.Entities, 3)
+	require.Equal(t, []string{ReasonIdentifier, ReasonNameBirthDate}, cluster.Reasons)
+
+	clusterID, linked := index.Linked(entities[1])
+	require.Equal(t, cluster.ID, clusterID)
+	require.Len(t, linked, 2)
+
+	byID, found := index.Get(cluster.ID)
+	require.True(t, found)
+	require.Equal(t, cluster, byID)
+
+	for _, entity := range entities[3:] {
+		clusterID, linked := index.Linked(entity)
+		require.Empty(t, clusterID)
+		require.Empty(t, linked)
+	}
+
+	// IDs don't depend on the order of records or other clusters
+	reversed := make([]search.Entity[search.Value], 0, len(entities))
+	for i := len(entities) - 1; i >= 0; i-- {
+		reversed = append(reversed, entities[i])
+	}
+	again := Resolve(reversed)
+	_, found = again.Get(cluster.ID)
+	require.True(t, found)
+
+	var nilIndex *Index
+	require.Equal(t, 0, nilIndex.Len())
+	_, found = nilIndex.Lookup(entities[0])
+	require.False(t, found)
+}
+
+func TestResolve_Addresses(t *testing.T) {
+	business := func(source search.SourceList, name, city string) search.Entity[search.Value] {
+		return search.Entity[search.Value]{
+			Name:     name,
+			Type:     search.EntityBusiness,
+			Source:   source,
+			SourceID: name,
+			Business: &search.Business{Name: name},
+			Addresses: []search.Address{
+				{City: city, Country: "IR"},
+			},
+		}
+	}
+
+	index := Resolve([]search.Entity[search.Value]{
+		business(search.SourceUSOFAC, "Tidewater Middle East Co.", "Tehran"),
+		business(search.SourceEUCSL, "TIDEWATER MIDDLE EAST CO", "TEHRAN"),
+		business(search.SourceUKCSL, "Tidewater Middle East Co", "Bandar Abbas"),
+		business(search.SourceUKSanctionsList, "TIDEWATER MIDDLE EAST CO.", "Bandar Abbas"),
+	})
+	require.Equal(t, 2, index.Len())
+
+	_, linked := index.Linked(business(search.SourceUSOFAC, "Tidewater Middle East Co.", "Tehran"))
+	require.Len(t, linked, 1)
+	require.Equal(t, search.SourceEUCSL, linked[0].Source)
+
+	// both UK lists are linked to each other
+	_, linked = index.Linked(business(search.SourceUKCSL, "Tidewater Middle East Co", "Bandar Abbas"))
+	require.Len(t, linked, 1)
+	require.Equal(t, search.SourceUKSanctionsList, linked[0].Source)
+}
+
+func TestResolve_IdentifierTypeAndCountry(t *testing.T) {
+	business := func(source search.SourceList, sourceID, name string, ids ...search.Identifier) search.Entity[search.Value] {
+		return search.Entity[search.Value]{
+			Name:     name,
+			Type:     search.EntityBusiness,
+			Source:   source,
+			SourceID: sourceID,
+			Business: &search.Business{Name: name, Identifier: ids},
+		}
+	}
+
+	entities := []search.Entity[search.Value]{
+		// lists name registration numbers differently and may leave out the country
+		business(search.SourceUSOFAC, "1", "Acme Trading", search.Identifier{Name: "Registration ID", Country: "Russia", Identifier: "1027700159497"}),
+		business(search.SourceEUCSL, "1", "ACME TRADING LLC", search.Identifier{Name: "Registration Number", Country: "RU", Identifier: "1027700159497"}),
+		business(search.SourceUSCSL, "1", "Acme Trading Company", search.Identifier{Name: "Company Number", Identifier: "1027700159497"}),
+		// the same number as a different kind of document isn't linked
+		business(search.SourceUNCSL, "1", "Third Company", search.Identifier{Name: "Tax ID No.", Country: "RU", Identifier: "1027700159497"}),
+	}
+
+	index := Resolve(entities)
+	require.Equal(t, 1, index.Len())
+
+	cluster, found := index.Lookup(entities[0])
+	require.True(t, found)
+	require.Len(t, cluster.Entities, 3)
+	require.Equal(t, []string{ReasonIdentifier}, cluster.Reasons)
+
+	_, found = index.Lookup(entities[3])
+	require.False(t, found)
+
+	// the same number from different countries isn't linked, and a record without a country
+	// can't be linked to either of them
+	entities = []search.Entity[search.Value]{
+		business(search.SourceUSOFAC, "1", "Acme Trading", search.Identifier{Name: "Registration ID", Country: "RU", Identifier: "1027700159497"}),
+		business(search.SourceEUCSL, "1", "Other Company", search.Identifier{Name: "Registration Number", Country: "UA", Identifier: "1027700159497"}),
+		business(search.SourceUSCSL, "1", "Someone Else", search.Identifier{Name: "Registration Number", Identifier: "1027700159497"}),
+	}
+	require.Equal(t, 0, Resolve(entities).Len())
+
+	// passports with and without a country are linked
+	withCountry := person(search.SourceUSOFAC, "2", "Jane Doe", nil, "X1234567")
+	withCountry.Person.GovernmentIDs[0].Country = "Venezuela"
+	withoutCountry := person(search.SourceEUCSL, "2", "Someone Else", nil, "X1234567")
+	require.Equal(t, 1, Resolve([]search.Entity[search.Value]{withCountry, withoutCountry}).Len())
+}
+
+func TestIdentifierType(t *testing.T) {
+	cases := map[string]string{
+		"Registration ID":                "registration",
+		"Registration Number":            "registration",
+		"Business Registration Document": "registration",
+		"commercial-registry":            "registration",
+		"Tax ID No.":                     "tax",
+		"Tax Identification Number":      "tax",
+		"INN":                            "tax",
+		"SWIFT/BIC":                      "swift",
+		"Passport":                       "passport",
+		"diplomatic-passport":            "passport",
+		"National ID No.":                "national",
+		"personal-id":                    "national",
+		"Innovation Certificate":         "other",
+		"":                               "other",
+	}
+	for idType, expected := range cases {
+		require.Equal(t, expected, identifierType(idType), idType)
+	}
+}
+
+func TestResolve_CommonKeys(t *testing.T) {
+	dob := time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var entities []search.Entity[search.Value]
+	for i := 0; i <= maxKeyMembers; i++ {
+		source := search.SourceUSOFAC
+		if i%2 == 0 {
+			source = search.SourceEUCSL
+		}
+		entities = append(entities, person(source, fmt.Sprintf("%d", i), "Mohammed Ali", &dob))
+	}
+	require.Equal(t, 0, Resolve(entities).Len())
+}
+
+func TestNormalize(t *testing.T) {
+	require.Equal(t, normalizeName("Nicolas Maduro Moros"), normalizeName("MADURO MOROS, Nicolas"))
+	require.Equal(t, "AB123456", normalizeIdentifier("ab-123 456"))
+	require.Empty(t, normalizeIdentifier("12-3"))
+
+	require.Equal(t, "VE", normalizeCountry("Venezuela"))
+	require.Equal(t, "VE", normalizeCountry(" ve "))
+	require.Equal(t, "RU", normalizeCountry("RUS"))
+	require.Equal(t, "SOVIET UNION", normalizeCountry("Soviet Union"))
+	require.Empty(t, normalizeCountry(""))
+}
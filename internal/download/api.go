@@ -0,0 +1,46 @@
+package download
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+)
+
+type Controller interface {
+	AppendRoutes(router *mux.Router) *mux.Router
+}
+
+func NewController(logger log.Logger, downloader Downloader) Controller {
+	return &controller{
+		logger:     logger,
+		downloader: downloader,
+	}
+}
+
+type controller struct {
+	logger     log.Logger
+	downloader Downloader
+}
+
+func (c *controller) AppendRoutes(router *mux.Router) *mux.Router {
+	router.
+		Name("Lists.v2").
+		Methods("GET").
+		Path("/v2/lists").
+		HandlerFunc(c.listStatus)
+
+	return router
+}
+
+type listsResponse struct {
+	Lists []ListStatus `json:"lists"`
+}
+
+func (c *controller) listStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(listsResponse{
+		Lists: c.downloader.Status(),
+	})
+}
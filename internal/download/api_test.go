@@ -0,0 +1,46 @@
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_ListStatus(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "opensanctions"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				LocalFile: filepath.Join("..", "..", "test", "testdata", "dpl.txt"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	_, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	NewController(log.NewTestLogger(), dl).AppendRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/lists", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp listsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Lists, len(builtinSources(Config{})))
+
+	dpl := findStatus(t, resp.Lists, "us_dpl")
+	require.Equal(t, 546, dpl.Entities)
+	require.NotNil(t, dpl.LastSuccess)
+	require.NotEmpty(t, dpl.ContentHash)
+}
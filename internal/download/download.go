@@ -2,36 +2,116 @@ package download
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/moov-io/watchman/pkg/ofac"
+	pkgdownload "github.com/moov-io/watchman/pkg/download"
 	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/sources"
 
 	"github.com/moov-io/base/log"
-	"golang.org/x/sync/errgroup"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type Downloader interface {
 	RefreshAll(ctx context.Context) (Stats, error)
+
+	// Status returns the most recent refresh of each list
+	Status() []ListStatus
+
+	// Ready returns an error when an enabled list hasn't been loaded, or its data is older than MaxListAge
+	Ready() error
+
+	// Restore uses entities from a snapshot as each list's previous refresh, so a list which fails
+	// its first refresh keeps them and the refresh guards compare against them. Each restored list's
+	// status reports the snapshot's entities as last loaded at createdAt.
+	Restore(createdAt time.Time, entities []search.Entity[search.Value])
 }
 
+// NewDownloader returns a Downloader for the built-in lists, custom lists and each source added with sources.Register
 func NewDownloader(logger log.Logger, conf Config) (Downloader, error) {
+	return newDownloader(logger, conf, sources.Registered())
+}
+
+func newDownloader(logger log.Logger, conf Config, registered []sources.Source) (Downloader, error) {
+	names := make(map[string]bool)
+	for _, source := range builtinSources(conf) {
+		names[source.Name()] = true
+	}
+	for _, list := range conf.CustomLists {
+		if list.Name == "" {
+			return nil, errors.New("custom list is missing a Name")
+		}
+		if names[list.Name] {
+			return nil, fmt.Errorf("custom list %s: name is already used by another list", list.Name)
+		}
+		if list.Directory == "" {
+			return nil, fmt.Errorf("custom list %s: missing Directory", list.Name)
+		}
+		names[list.Name] = true
+	}
+	for _, source := range registered {
+		if names[source.Name()] {
+			return nil, fmt.Errorf("source %s: name is already used by another list", source.Name())
+		}
+		names[source.Name()] = true
+	}
+
+	httpClient, err := pkgdownload.NewHTTPClient(conf.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("setting up HTTP client: %w", err)
+	}
+
 	return &downloader{
-		logger: logger,
-		conf:   conf,
+		logger:     logger,
+		conf:       conf,
+		httpClient: httpClient,
+		sources:    conf.sources(registered),
+		previous:   make(map[string]loadedList),
+		status:     make(map[string]ListStatus),
 	}, nil
 }
 
 type downloader struct {
-	logger log.Logger
-	conf   Config
+	logger     log.Logger
+	conf       Config
+	httpClient *http.Client
+	sources    []sources.Source
+
+	mu       sync.Mutex
+	previous map[string]loadedList
+	status   map[string]ListStatus
+}
+
+// loadedList is the most recent data from a list, kept around when the list
+// has its own RefreshInterval and isn't due to be loaded again.
+type loadedList struct {
+	loadedAt time.Time
+	lists    []preparedList
 }
 
+var (
+	refreshErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "watchman_list_refresh_errors",
+		Help: "Counter of errors loading a list",
+	}, []string{"list"})
+)
+
 func (dl *downloader) RefreshAll(ctx context.Context) (Stats, error) {
 	stats := Stats{
-		Lists:     make(map[string]int),
-		StartedAt: time.Now().In(time.UTC),
+		Lists:         make(map[string]int),
+		SkippedLists:  make(map[string]string),
+		RejectedLists: make(map[string]string),
+		StartedAt:     time.Now().In(time.UTC),
 	}
 
 	logger := dl.logger.Info().With(log.Fields{
@@ -41,171 +121,396 @@ func (dl *downloader) RefreshAll(ctx context.Context) (Stats, error) {
 	start := time.Now()
 	logger.Info().Log("starting list refresh")
 
-	g, ctx := errgroup.WithContext(ctx)
-	preparedLists := make(chan preparedList, 1)
-
-	g.Go(func() error {
-		err := loadOFACRecords(ctx, logger, dl.conf, preparedLists)
-		if err != nil {
-			return fmt.Errorf("loading OFAC records: %w", err)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var preparedLists []preparedList
+	refreshed := make(map[string][]preparedList)
+
+	for _, source := range dl.sources {
+		name := source.Name()
+		if !dl.conf.listEnabled(source) {
+			logger.Info().Logf("skipping %s, list is disabled", name)
+			stats.SkippedLists[name] = "disabled"
+			dl.updateStatus(name, func(status *ListStatus) {
+				status.Enabled = false
+				status.Skipped = "disabled"
+			})
+			continue
 		}
-		return nil
-	})
 
-	err := g.Wait()
-	close(preparedLists)
+		if previous, ok := dl.reusableList(name, start); ok {
+			logger.Info().Logf("skipping %s, refresh interval has not elapsed", name)
+			stats.SkippedLists[name] = fmt.Sprintf("refresh interval has not elapsed since %v", previous.loadedAt.Format(time.RFC3339))
+			preparedLists = append(preparedLists, previous.lists...)
+			dl.updateStatus(name, func(status *ListStatus) {
+				status.Enabled = true
+				status.Skipped = stats.SkippedLists[name]
+			})
+			continue
+		}
 
-	if err != nil {
-		return stats, fmt.Errorf("problem loading lists: %v", err)
+		source := source
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Lists loaded before can skip parsing files which haven't changed since they were accepted
+			previous, hasPrevious := dl.previousList(name)
+			var acceptedHash string
+			if hasPrevious {
+				_, acceptedHash, _ = listDetails(previous.lists)
+			}
+
+			attemptedAt := time.Now().In(time.UTC)
+			lists, err := loadSource(ctx, logger, dl.conf, source, dl.downloadOptions(name), acceptedHash)
+
+			// Record the outcome once we know which entities the list has
+			var loaded []preparedList
+			var problem, skipped string
+			defer func() {
+				dl.updateStatus(name, func(status *ListStatus) {
+					status.Enabled = true
+					status.LastAttempt = &attemptedAt
+					status.Duration = time.Since(attemptedAt).String()
+					status.Skipped = skipped
+					status.Errors = nil
+					if problem != "" {
+						status.Errors = []string{problem}
+					} else {
+						status.LastSuccess = &attemptedAt
+					}
+					status.Entities, status.ContentHash, status.PublishedAt = listDetails(loaded)
+				})
+			}()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if errors.Is(err, errNotModified) {
+				logger.Info().Logf("skipping %s, files have not been modified", name)
+				skipped = "not modified since the previous refresh"
+				stats.SkippedLists[name] = skipped
+				preparedLists = append(preparedLists, previous.lists...)
+				refreshed[name] = previous.lists
+				loaded = previous.lists
+				return
+			}
+
+			// A failed list keeps its previous entities while the other lists are updated
+			if err != nil {
+				refreshErrors.WithLabelValues(name).Inc()
+				logger.Error().Logf("problem loading %s records: %v", name, err)
+
+				problem = err.Error()
+				stats.Errors = append(stats.Errors, ListError{List: name, Error: problem})
+				preparedLists = append(preparedLists, previous.lists...)
+				loaded = previous.lists
+				return
+			}
+
+			// Keep the previous entities when the refreshed data looks broken
+			if failure := dl.conf.guards(name).check(lists, previous.lists); failure != nil {
+				guardFailures.WithLabelValues(name, failure.guard).Inc()
+				logger.Error().Logf("rejected %s refresh: %v", name, failure)
+
+				problem = failure.Error()
+				stats.RejectedLists[name] = problem
+				preparedLists = append(preparedLists, previous.lists...)
+				loaded = previous.lists
+				return
+			}
+
+			preparedLists = append(preparedLists, lists...)
+			refreshed[name] = lists
+			loaded = lists
+		}()
 	}
+	wg.Wait()
+
+	slices.SortFunc(stats.Errors, func(a, b ListError) int {
+		return strings.Compare(a.List, b.List)
+	})
 
 	// accumulate the lists
-	for list := range preparedLists {
-		stats.Lists[string(list.ListName)] = len(list.Entities)
+	for _, list := range preparedLists {
+		stats.Lists[string(list.ListName)] += len(list.Entities)
 		stats.Entities = append(stats.Entities, list.Entities...)
 	}
 
+	dl.mu.Lock()
+	for name, lists := range refreshed {
+		dl.previous[name] = loadedList{
+			loadedAt: start,
+			lists:    lists,
+		}
+	}
+	dl.mu.Unlock()
+
 	logger.Info().Logf("finished all lists: %v", time.Since(start))
 
 	stats.EndedAt = time.Now().In(time.UTC)
 
+	// Only fail when there's nothing to search against
+	if len(stats.Errors) > 0 && len(stats.Entities) == 0 {
+		var errs []error
+		for _, listErr := range stats.Errors {
+			errs = append(errs, fmt.Errorf("%s: %s", listErr.List, listErr.Error))
+		}
+		return stats, fmt.Errorf("problem loading lists: %w", errors.Join(errs...))
+	}
+
 	return stats, nil
 }
 
-type preparedList struct {
-	ListName search.SourceList
-	Entities []search.Entity[search.Value]
+func (dl *downloader) Restore(createdAt time.Time, entities []search.Entity[search.Value]) {
+	bySource := make(map[search.SourceList][]search.Entity[search.Value])
+	for _, entity := range entities {
+		bySource[entity.Source] = append(bySource[entity.Source], entity)
+	}
+
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	// Entities are matched to the source with the same name, which every built-in and custom list uses
+	for _, source := range dl.sources {
+		name := source.Name()
+		found, exists := bySource[search.SourceList(name)]
+		if _, loaded := dl.previous[name]; !exists || loaded {
+			continue
+		}
+		// Without a loadedAt each list is refreshed right away, even with its own RefreshInterval
+		dl.previous[name] = loadedList{
+			lists: []preparedList{{
+				ListName: search.SourceList(name),
+				Entities: found,
+			}},
+		}
+		if _, refreshed := dl.status[name]; !refreshed && dl.conf.listEnabled(source) {
+			lastSuccess := createdAt
+			dl.status[name] = ListStatus{
+				Name:        name,
+				Enabled:     true,
+				Entities:    len(found),
+				LastSuccess: &lastSuccess,
+			}
+		}
+	}
 }
 
-func loadOFACRecords(ctx context.Context, logger log.Logger, conf Config, responseCh chan preparedList) error {
-	start := time.Now()
-	files, err := ofac.Download(ctx, logger, conf.InitialDataDirectory)
-	if err != nil {
-		return fmt.Errorf("download: %v", err)
+// downloadOptions returns how a list's files are downloaded
+func (dl *downloader) downloadOptions(name string) pkgdownload.Options {
+	client := dl.httpClient
+	if timeout := dl.conf.Lists[name].Timeout; timeout > 0 {
+		withTimeout := *client
+		withTimeout.Timeout = timeout
+		client = &withTimeout
+	}
+
+	return pkgdownload.Options{
+		CacheDirectory: dl.conf.CacheDirectory,
+		Client:         client,
+		Headers:        dl.conf.HTTP.Headers,
+		Retry:          dl.conf.HTTP.Retry,
+	}
+}
+
+// previousList returns the most recently loaded data for a list
+func (dl *downloader) previousList(name string) (loadedList, bool) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	previous, exists := dl.previous[name]
+	return previous, exists
+}
+
+// reusableList returns the previously loaded data for a list when its RefreshInterval hasn't elapsed.
+func (dl *downloader) reusableList(name string, now time.Time) (loadedList, bool) {
+	interval := dl.conf.Lists[name].RefreshInterval
+	if interval <= 0 {
+		return loadedList{}, false
+	}
+
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	previous, exists := dl.previous[name]
+	if !exists || now.Sub(previous.loadedAt) >= interval {
+		return loadedList{}, false
 	}
-	if len(files) == 0 {
-		return fmt.Errorf("unexpected %d OFAC files found", len(files))
+	return previous, true
+}
+
+func (c Config) listEnabled(source sources.Source) bool {
+	name := source.Name()
+	if slices.ContainsFunc(c.DisabledLists, func(disabled string) bool {
+		return strings.EqualFold(strings.TrimSpace(disabled), name)
+	}) {
+		return false
+	}
+	if enabled := c.Lists[name].Enabled; enabled != nil {
+		return *enabled
+	}
+	if list, ok := source.(listSource); ok && list.disabledByDefault {
+		return false
+	}
+	return true
+}
+
+// MinimumRefreshInterval returns the shortest RefreshInterval of any enabled list, or zero if none are set.
+func (c Config) MinimumRefreshInterval() time.Duration {
+	builtin := make(map[string]sources.Source)
+	for _, source := range builtinSources(c) {
+		builtin[source.Name()] = source
 	}
 
-	logger.Debug().Logf("finished OFAC download: %v", time.Since(start))
-	start = time.Now()
+	var out time.Duration
+	for name, list := range c.Lists {
+		source, exists := builtin[name]
+		if !exists {
+			source = listSource{name: name}
+		}
+		if list.RefreshInterval <= 0 || !c.listEnabled(source) {
+			continue
+		}
+		if out == 0 || list.RefreshInterval < out {
+			out = list.RefreshInterval
+		}
+	}
+	return out
+}
+
+var (
+	// errNotModified is returned from downloadListFiles when none of a list's files have changed
+	// since its previous refresh, which is reused instead of parsing the files again.
+	errNotModified = errors.New("files not modified")
+)
+
+type downloadFunc func(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error)
 
-	res, err := ofac.Read(files)
+// downloadListFiles returns each file of a list, or an error when any of filenames are missing. When every file
+// is an unchanged copy from the cache with the acceptedHash of the files the list was last loaded from
+// errNotModified is returned instead.
+func downloadListFiles(ctx context.Context, logger log.Logger, conf Config, name string, filenames []string, fallback downloadFunc, opts pkgdownload.Options, acceptedHash string) (map[string]io.ReadCloser, error) {
+	files, err := getListFiles(ctx, logger, conf, name, filenames, fallback, opts)
 	if err != nil {
-		return err
-	}
-
-	entities := ofac.GroupIntoEntities(res.SDNs, res.Addresses, res.SDNComments, res.AlternateIdentities)
-	logger.Debug().Logf("finished OFAC preperation: %v", time.Since(start))
-
-	responseCh <- preparedList{
-		ListName: search.SourceUSOFAC,
-		Entities: entities,
-	}
-
-	return nil
-}
-
-// 	"github.com/moov-io/watchman/pkg/csl_eu"
-// 	"github.com/moov-io/watchman/pkg/csl_uk"
-// 	"github.com/moov-io/watchman/pkg/csl_us"
-
-// func cslUSRecords(logger log.Logger, initialDir string) (csl_us.CSL, error) {
-// 	file, err := csl_us.Download(logger, initialDir)
-// 	if err != nil {
-// 		logger.Warn().Logf("skipping CSL US download: %v", err)
-// 		return csl_us.CSL{}, nil
-// 	}
-// 	cslRecords, err := csl_us.ReadFile(file["csl.csv"])
-// 	if err != nil {
-// 		return csl_us.CSL{}, fmt.Errorf("reading CSL US: %w", err)
-// 	}
-// 	return cslRecords, nil
-// }
-
-// func euCSLRecords(logger log.Logger, initialDir string) ([]csl_eu.CSLRecord, error) {
-// 	file, err := csl_eu.DownloadEU(logger, initialDir)
-// 	if err != nil {
-// 		logger.Warn().Logf("skipping EU CSL download: %v", err)
-// 		// no error to return because we skip the download
-// 		return nil, nil
-// 	}
-
-// 	cslRecords, _, err := csl_eu.ParseEU(file["eu_csl.csv"])
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return cslRecords, err
-
-// }
-
-// func ukCSLRecords(logger log.Logger, initialDir string) ([]csl_uk.CSLRecord, error) {
-// 	file, err := csl_uk.DownloadCSL(logger, initialDir)
-// 	if err != nil {
-// 		logger.Warn().Logf("skipping UK CSL download: %v", err)
-// 		// no error to return because we skip the download
-// 		return nil, nil
-// 	}
-// 	cslRecords, _, err := csl_uk.ReadCSLFile(file["ConList.csv"])
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return cslRecords, err
-// }
-
-// func ukSanctionsListRecords(logger log.Logger, initialDir string) ([]csl_uk.SanctionsListRecord, error) {
-// 	file, err := csl_uk.DownloadSanctionsList(logger, initialDir)
-// 	if file == nil || err != nil {
-// 		logger.Warn().Logf("skipping UK Sanctions List download: %v", err)
-// 		// no error to return because we skip the download
-// 		return nil, nil
-// 	}
-
-// 	records, _, err := csl_uk.ReadSanctionsListFile(file["UK_Sanctions_List.ods"])
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return records, err
-// }
-
-// 	var euCSLs []Result[csl_eu.CSLRecord]
-// 	withEUScreeningList := cmp.Or(os.Getenv("WITH_EU_SCREENING_LIST"), "true")
-// 	if strx.Yes(withEUScreeningList) {
-// 		euConsolidatedList, err := euCSLRecords(s.logger, initialDir)
-// 		if err != nil {
-// 			stats.Errors = append(stats.Errors, fmt.Errorf("EUCSL: %v", err))
-// 		}
-// 		euCSLs = precomputeCSLEntities[csl_eu.CSLRecord](euConsolidatedList, s.pipe)
-// 	}
-
-// 	var ukCSLs []Result[csl_uk.CSLRecord]
-// 	withUKCSLSanctionsList := cmp.Or(os.Getenv("WITH_UK_CSL_SANCTIONS_LIST"), "true")
-// 	if strx.Yes(withUKCSLSanctionsList) {
-// 		ukConsolidatedList, err := ukCSLRecords(s.logger, initialDir)
-// 		if err != nil {
-// 			stats.Errors = append(stats.Errors, fmt.Errorf("UKCSL: %v", err))
-// 		}
-// 		ukCSLs = precomputeCSLEntities[csl_uk.CSLRecord](ukConsolidatedList, s.pipe)
-// 	}
-
-// 	var ukSLs []Result[csl_uk.SanctionsListRecord]
-// 	withUKSanctionsList := os.Getenv("WITH_UK_SANCTIONS_LIST")
-// 	if strings.ToLower(withUKSanctionsList) == "true" {
-// 		ukSanctionsList, err := ukSanctionsListRecords(s.logger, initialDir)
-// 		if err != nil {
-// 			stats.Errors = append(stats.Errors, fmt.Errorf("UKSanctionsList: %v", err))
-// 		}
-// 		ukSLs = precomputeCSLEntities[csl_uk.SanctionsListRecord](ukSanctionsList, s.pipe)
-
-// 		stats.UKSanctionsList = len(ukSLs)
-// 	}
-
-// 	// csl records from US downloaded here
-// 	var usConsolidatedLists csl_us.CSL
-// 	withUSConsolidatedLists := cmp.Or(os.Getenv("WITH_US_CSL_SANCTIONS_LIST"), "true")
-// 	if strx.Yes(withUSConsolidatedLists) {
-// 		usConsolidatedLists, err = cslUSRecords(s.logger, initialDir)
-// 		if err != nil {
-// 			stats.Errors = append(stats.Errors, fmt.Errorf("US CSL: %v", err))
-// 		}
-// 	}
+		return nil, err
+	}
+
+	// Downloads which failed are left out of files, which must fail the refresh so the list keeps its previous entities
+	if missing := missingFiles(files, filenames); len(missing) > 0 {
+		for _, file := range files {
+			file.Close()
+		}
+		return nil, fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	// Cached files which were rejected by validation or a refresh guard have a different hash and are parsed again
+	if acceptedHash != "" && len(files) > 0 && allNotModified(files) && cachedHash(files) == acceptedHash {
+		for _, file := range files {
+			file.Close()
+		}
+		return nil, errNotModified
+	}
+	return hashFiles(files), nil
+}
+
+func missingFiles(files map[string]io.ReadCloser, filenames []string) []string {
+	var out []string
+	for _, filename := range filenames {
+		if _, exists := files[filename]; !exists {
+			out = append(out, filename)
+		}
+	}
+	return out
+}
+
+// cachedHash combines the hash of each cached file the same way as contentHash
+func cachedHash(files map[string]io.ReadCloser) string {
+	sums := make(map[string]string)
+	for name, file := range files {
+		sum := pkgdownload.CachedSHA256(file)
+		if sum == "" {
+			return ""
+		}
+		sums[name] = sum
+	}
+	return combineHashes(sums)
+}
+
+func allNotModified(files map[string]io.ReadCloser) bool {
+	for _, file := range files {
+		if !pkgdownload.NotModified(file) {
+			return false
+		}
+	}
+	return true
+}
+
+// getListFiles honors the LocalFile and DownloadURL overrides from the list's config
+// before falling back to the package's default download.
+func getListFiles(ctx context.Context, logger log.Logger, conf Config, name string, filenames []string, fallback downloadFunc, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+	listConf := conf.Lists[name]
+	initialDir := conf.InitialDataDirectory
+
+	if listConf.LocalFile != "" {
+		info, err := os.Stat(listConf.LocalFile)
+		if err != nil {
+			return nil, fmt.Errorf("local file: %w", err)
+		}
+		if info.IsDir() {
+			initialDir = listConf.LocalFile
+		} else {
+			if len(filenames) > 1 {
+				return nil, fmt.Errorf("local file must be a directory for lists with %d files", len(filenames))
+			}
+			fd, err := os.Open(listConf.LocalFile)
+			if err != nil {
+				return nil, fmt.Errorf("local file: %w", err)
+			}
+
+			// Lists without fixed filenames read the file under its own name
+			filename := filepath.Base(listConf.LocalFile)
+			if len(filenames) == 1 {
+				filename = filenames[0]
+			}
+			return map[string]io.ReadCloser{
+				filename: fd,
+			}, nil
+		}
+	}
+
+	if listConf.DownloadURL != "" {
+		if len(filenames) == 0 {
+			return nil, errors.New("download URL is not supported for lists without filenames")
+		}
+		if len(filenames) > 1 && !strings.Contains(listConf.DownloadURL, "%s") {
+			return nil, fmt.Errorf("download URL must contain %%s for lists with %d files", len(filenames))
+		}
+
+		addrs := make(map[string]string)
+		for _, filename := range filenames {
+			addr := listConf.DownloadURL
+			if strings.Contains(addr, "%s") {
+				addr = fmt.Sprintf(addr, filename)
+			}
+			addrs[filename] = addr
+		}
+		dl := pkgdownload.New(logger, pkgdownload.HTTPClient)
+		dl.Options = opts
+		return dl.GetFiles(ctx, initialDir, addrs)
+	}
+
+	return fallback(ctx, logger, initialDir, opts)
+}
+
+type preparedList struct {
+	ListName search.SourceList
+	Entities []search.Entity[search.Value]
+
+	// Records is how many records were read from the list's files, when the list can tell.
+	// It's compared against Entities by the MinParseRatio guard.
+	Records int
+
+	ContentHash string
+	PublishedAt *time.Time
+}
@@ -0,0 +1,499 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pkgdownload "github.com/moov-io/watchman/pkg/download"
+	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/sources"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_listEnabled(t *testing.T) {
+	disabled := false
+	conf := Config{
+		DisabledLists: []string{"US_OFAC"},
+		Lists: map[string]ListConfig{
+			"eu_csl": {Enabled: &disabled},
+		},
+	}
+	builtin := func(name string) sources.Source {
+		for _, source := range builtinSources(conf) {
+			if source.Name() == name {
+				return source
+			}
+		}
+		t.Fatalf("unknown list %s", name)
+		return nil
+	}
+	require.False(t, conf.listEnabled(builtin("us_ofac")))
+	require.False(t, conf.listEnabled(builtin("eu_csl")))
+	require.True(t, conf.listEnabled(builtin("uk_csl")))
+
+	// the UK Sanctions List is only loaded when enabled
+	t.Setenv("WITH_UK_SANCTIONS_LIST", "")
+	require.False(t, conf.listEnabled(builtin("uk_sanctions_list")))
+
+	t.Setenv("WITH_UK_SANCTIONS_LIST", "true")
+	require.True(t, conf.listEnabled(builtin("uk_sanctions_list")))
+
+	conf.Lists["uk_sanctions_list"] = ListConfig{Enabled: &disabled}
+	require.False(t, conf.listEnabled(builtin("uk_sanctions_list")))
+
+	// sources which aren't built-in are enabled by default
+	require.True(t, conf.listEnabled(listSource{name: "custom"}))
+}
+
+func TestConfig_MinimumRefreshInterval(t *testing.T) {
+	disabled := false
+	conf := Config{
+		DisabledLists: []string{"us_ofac"},
+		Lists: map[string]ListConfig{
+			"us_ofac": {RefreshInterval: time.Minute},
+			"eu_csl":  {RefreshInterval: time.Hour},
+			"uk_csl":  {RefreshInterval: time.Second, Enabled: &disabled},
+			"us_csl":  {RefreshInterval: 2 * time.Hour},
+		},
+	}
+	require.Equal(t, time.Hour, conf.MinimumRefreshInterval())
+	require.Equal(t, time.Duration(0), Config{}.MinimumRefreshInterval())
+}
+
+func TestDownloader_RefreshAll_ListConfig(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Lists: map[string]ListConfig{
+			"us_csl": {
+				RefreshInterval: time.Hour,
+				LocalFile:       filepath.Join("..", "..", "test", "testdata", "csl.csv"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Greater(t, stats.Lists[string(search.SourceUSCSL)], 0)
+	require.Equal(t, "disabled", stats.SkippedLists["us_ofac"])
+	require.NotContains(t, stats.SkippedLists, "us_csl")
+
+	// The second refresh reuses the US CSL data since its interval hasn't elapsed
+	again, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stats.Lists, again.Lists)
+	require.Len(t, again.Entities, len(stats.Entities))
+	require.Contains(t, again.SkippedLists["us_csl"], "refresh interval has not elapsed")
+}
+
+func TestDownloader_RefreshAll_OFACAdvanced(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Lists: map[string]ListConfig{
+			"us_ofac": {
+				Format:    "xml",
+				LocalFile: filepath.Join("..", "..", "pkg", "ofac", "testdata", "sdn_advanced.xml"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Lists[string(search.SourceUSOFAC)])
+}
+
+func TestDownloader_RefreshAll_OFACNonSDNAdvanced(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Lists: map[string]ListConfig{
+			"us_non_sdn": {
+				Format: "xml",
+				// CONS_ADVANCED.XML uses the same format as the SDN file
+				LocalFile: filepath.Join("..", "..", "pkg", "ofac", "testdata", "sdn_advanced.xml"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Lists[string(search.SourceUSNonSDN)])
+	for _, entity := range stats.Entities {
+		require.Equal(t, search.SourceUSNonSDN, entity.Source)
+	}
+}
+
+func TestDownloader_RefreshAll_OFACNonSDN(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Lists: map[string]ListConfig{
+			"us_non_sdn": {
+				LocalFile: filepath.Join("..", "..", "pkg", "ofac", "testdata"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists[string(search.SourceUSNonSDN)])
+	require.NotContains(t, stats.Lists, string(search.SourceUSOFAC))
+	for _, entity := range stats.Entities {
+		require.Equal(t, search.SourceUSNonSDN, entity.Source)
+	}
+}
+
+func TestDownloader_RefreshAll_OpenSanctions(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Lists: map[string]ListConfig{
+			"opensanctions": {
+				LocalFile: filepath.Join("..", "..", "pkg", "opensanctions", "testdata"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 6, stats.Lists[string(search.SourceOpenSanctions)])
+
+	// Without a LocalFile the list is skipped
+	conf.Lists = nil
+	dl, err = NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, stats.Entities)
+}
+
+func TestDownloader_CustomLists(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		CustomLists: []CustomList{
+			{
+				Name:      "internal_fraud",
+				Directory: filepath.Join("..", "..", "pkg", "custom_list", "testdata", "fraud"),
+			},
+			{
+				Name:      "former_customers",
+				Directory: filepath.Join("..", "..", "pkg", "custom_list", "testdata", "customers"),
+				Columns: map[string]string{
+					"name": "Full Name",
+					"type": "Entity Type",
+				},
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Lists["internal_fraud"])
+	require.Equal(t, 2, stats.Lists["former_customers"])
+
+	// custom lists can be disabled like any other list
+	conf.DisabledLists = append(conf.DisabledLists, "former_customers")
+	dl, err = NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, stats.Lists, "former_customers")
+	require.Equal(t, "disabled", stats.SkippedLists["former_customers"])
+}
+
+func TestNewDownloader_InvalidCustomLists(t *testing.T) {
+	logger := log.NewTestLogger()
+
+	_, err := NewDownloader(logger, Config{CustomLists: []CustomList{{Directory: "/tmp"}}})
+	require.ErrorContains(t, err, "missing a Name")
+
+	_, err = NewDownloader(logger, Config{CustomLists: []CustomList{{Name: "us_ofac", Directory: "/tmp"}}})
+	require.ErrorContains(t, err, "already used")
+
+	_, err = NewDownloader(logger, Config{CustomLists: []CustomList{{Name: "internal"}}})
+	require.ErrorContains(t, err, "missing Directory")
+}
+
+func TestDownloader_RefreshAll_DPL(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				LocalFile: filepath.Join("..", "..", "test", "testdata", "dpl.txt"),
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+}
+
+func TestDownloadListFiles_LocalFile(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewTestLogger()
+
+	conf := Config{
+		Lists: map[string]ListConfig{
+			"us_ofac": {LocalFile: filepath.Join("..", "..", "test", "testdata", "sdn.csv")},
+		},
+	}
+	_, err := downloadListFiles(ctx, logger, conf, "us_ofac", ofacFilenames, nil, pkgdownload.Options{}, "")
+	require.ErrorContains(t, err, "must be a directory")
+
+	conf.Lists["us_ofac"] = ListConfig{
+		DownloadURL: "https://example.com/ofac.csv",
+	}
+	_, err = downloadListFiles(ctx, logger, conf, "us_ofac", ofacFilenames, nil, pkgdownload.Options{}, "")
+	require.ErrorContains(t, err, "must contain %s")
+}
+
+func TestDownloader_RefreshAll_PartialFailure(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal.csv"), []byte("name,type\nJohn Doe,person\nAcme Corp,business\n"), 0600))
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				LocalFile: filepath.Join("..", "..", "test", "testdata", "dpl.txt"),
+			},
+		},
+		CustomLists: []CustomList{
+			{Name: "internal", Directory: dir},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists["internal"])
+	require.Empty(t, stats.Errors)
+
+	// A list which fails keeps its previous entities while the others refresh
+	require.NoError(t, os.RemoveAll(dir))
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists["internal"])
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, "internal", stats.Errors[0].List)
+	require.NotEmpty(t, stats.Errors[0].Error)
+
+	// Without any entities the refresh fails
+	conf.DisabledLists = append(conf.DisabledLists, "us_dpl")
+	dl, err = NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.ErrorContains(t, err, "problem loading lists: internal:")
+	require.Len(t, stats.Errors, 1)
+}
+
+func TestDownloader_RefreshAll_NotModified(t *testing.T) {
+	dplContents, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(dplContents)
+	}))
+	defer server.Close()
+
+	conf := Config{
+		CacheDirectory: t.TempDir(),
+		DisabledLists:  []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				DownloadURL: server.URL + "/dpl.txt",
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.NotContains(t, stats.SkippedLists, "us_dpl")
+
+	// unchanged files aren't parsed again
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.Equal(t, "not modified since the previous refresh", stats.SkippedLists["us_dpl"])
+
+	// a new downloader reads the cached copy
+	dl, err = NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.NotContains(t, stats.SkippedLists, "us_dpl")
+}
+
+func TestDownloader_RefreshAll_NotModifiedAfterRejection(t *testing.T) {
+	dplContents, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+	lines := strings.SplitAfter(string(dplContents), "\n")
+	truncated := []byte(strings.Join(lines[:11], ""))
+
+	var version atomic.Int32
+	version.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"v%d"`, version.Load())
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if version.Load() == 1 {
+			w.Write(dplContents)
+		} else {
+			w.Write(truncated)
+		}
+	}))
+	defer server.Close()
+
+	conf := Config{
+		CacheDirectory: t.TempDir(),
+		DisabledLists:  []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				DownloadURL: server.URL + "/dpl.txt",
+			},
+		},
+		Guards: RefreshGuards{
+			MaxDropPercent: 50,
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+
+	// the truncated file is rejected
+	version.Store(2)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.Contains(t, stats.RejectedLists["us_dpl"], "max_drop")
+
+	// and stays rejected after the server reports it hasn't changed
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.Contains(t, stats.RejectedLists["us_dpl"], "max_drop")
+	require.NotContains(t, stats.SkippedLists, "us_dpl")
+
+	status := findStatus(t, dl.Status(), "us_dpl")
+	require.Len(t, status.Errors, 1)
+}
+
+func TestDownloader_RefreshAll_Outage(t *testing.T) {
+	dplContents, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+
+	var outage atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if outage.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(dplContents)
+	}))
+	defer server.Close()
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				DownloadURL: server.URL + "/dpl.txt",
+			},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+
+	// A file which can't be downloaded fails the list, which keeps its previous entities
+	outage.Store(true)
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 546, stats.Lists[string(search.SourceUSDPL)])
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, "download: missing dpl.txt", stats.Errors[0].Error)
+
+	status := findStatus(t, dl.Status(), "us_dpl")
+	require.Equal(t, []string{"download: missing dpl.txt"}, status.Errors)
+	require.Equal(t, 546, status.Entities)
+}
+
+func TestDownloader_downloadOptions(t *testing.T) {
+	conf := Config{
+		CacheDirectory: "/data/cache",
+		HTTP: pkgdownload.HTTPConfig{
+			Timeout: time.Minute,
+			Headers: map[string]string{"X-Api-Key": "secret"},
+		},
+		Lists: map[string]ListConfig{
+			"us_ofac": {Timeout: 5 * time.Minute},
+		},
+	}
+	d, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	dl, ok := d.(*downloader)
+	require.True(t, ok)
+
+	opts := dl.downloadOptions("eu_csl")
+	require.Equal(t, "/data/cache", opts.CacheDirectory)
+	require.Equal(t, time.Minute, opts.Client.Timeout)
+	require.Equal(t, "secret", opts.Headers["X-Api-Key"])
+
+	// lists can have a longer timeout
+	opts = dl.downloadOptions("us_ofac")
+	require.Equal(t, 5*time.Minute, opts.Client.Timeout)
+	require.Equal(t, time.Minute, dl.httpClient.Timeout)
+
+	_, err = NewDownloader(log.NewTestLogger(), Config{
+		HTTP: pkgdownload.HTTPConfig{CABundleFile: filepath.Join(t.TempDir(), "missing.pem")},
+	})
+	require.ErrorContains(t, err, "reading CA bundle")
+}
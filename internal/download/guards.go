@@ -0,0 +1,100 @@
+package download
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RefreshGuards reject refreshed list data which looks truncated or corrupt. The list's
+// previous entities are kept when any guard fails. Zero values disable each guard.
+type RefreshGuards struct {
+	// MaxDropPercent is the largest decrease (0-100) in entities compared to the previous refresh
+	MaxDropPercent float64
+
+	// MinEntities is the fewest entities the list can have
+	MinEntities int
+
+	// MinParseRatio is the smallest fraction (0.0-1.0) of records read from the list's files
+	// which must be converted into entities
+	MinParseRatio float64
+}
+
+var (
+	guardFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "watchman_list_refresh_guard_failures",
+		Help: "Counter of list refreshes rejected by a refresh guard",
+	}, []string{"list", "guard"})
+)
+
+// guardFailure describes which guard rejected a list's refresh and why
+type guardFailure struct {
+	guard  string
+	reason string
+}
+
+func (f *guardFailure) Error() string {
+	return fmt.Sprintf("%s: %s", f.guard, f.reason)
+}
+
+// guards returns the refresh guards for a list. Values set in the list's config replace the defaults.
+func (c Config) guards(name string) RefreshGuards {
+	out := c.Guards
+
+	override := c.Lists[name].Guards
+	if override.MaxDropPercent > 0 {
+		out.MaxDropPercent = override.MaxDropPercent
+	}
+	if override.MinEntities > 0 {
+		out.MinEntities = override.MinEntities
+	}
+	if override.MinParseRatio > 0 {
+		out.MinParseRatio = override.MinParseRatio
+	}
+	return out
+}
+
+// check compares refreshed lists against the guards and the list's previous refresh, which is empty on the first refresh.
+func (g RefreshGuards) check(lists []preparedList, previous []preparedList) *guardFailure {
+	var entities, records int
+	for _, list := range lists {
+		entities += len(list.Entities)
+		records += list.Records
+	}
+
+	if g.MinEntities > 0 && entities < g.MinEntities {
+		return &guardFailure{
+			guard:  "min_entities",
+			reason: fmt.Sprintf("found %d entities, but at least %d are required", entities, g.MinEntities),
+		}
+	}
+
+	if g.MinParseRatio > 0 && records > 0 {
+		ratio := float64(entities) / float64(records)
+		if ratio < g.MinParseRatio {
+			return &guardFailure{
+				guard:  "parse_ratio",
+				reason: fmt.Sprintf("%d of %d records were parsed (%.1f%%), but %.1f%% are required", entities, records, ratio*100, g.MinParseRatio*100),
+			}
+		}
+	}
+
+	if g.MaxDropPercent > 0 {
+		var previousEntities int
+		for _, list := range previous {
+			previousEntities += len(list.Entities)
+		}
+		if previousEntities > 0 && entities < previousEntities {
+			drop := float64(previousEntities-entities) / float64(previousEntities) * 100
+			if drop > g.MaxDropPercent {
+				return &guardFailure{
+					guard:  "max_drop",
+					reason: fmt.Sprintf("entities dropped %.1f%% from %d to %d, but at most %.1f%% is allowed", drop, previousEntities, entities, g.MaxDropPercent),
+				}
+			}
+		}
+	}
+
+	return nil
+}
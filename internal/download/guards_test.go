@@ -0,0 +1,136 @@
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_guards(t *testing.T) {
+	conf := Config{
+		Guards: RefreshGuards{MaxDropPercent: 20, MinEntities: 100},
+		Lists: map[string]ListConfig{
+			"us_ofac": {Guards: RefreshGuards{MinEntities: 5000, MinParseRatio: 0.99}},
+		},
+	}
+
+	require.Equal(t, RefreshGuards{MaxDropPercent: 20, MinEntities: 100}, conf.guards("eu_csl"))
+	require.Equal(t, RefreshGuards{MaxDropPercent: 20, MinEntities: 5000, MinParseRatio: 0.99}, conf.guards("us_ofac"))
+}
+
+func TestRefreshGuards_check(t *testing.T) {
+	list := func(entities, records int) []preparedList {
+		return []preparedList{{
+			ListName: search.SourceUSOFAC,
+			Entities: make([]search.Entity[search.Value], entities),
+			Records:  records,
+		}}
+	}
+
+	// no guards
+	require.Nil(t, RefreshGuards{}.check(list(0, 0), list(100, 0)))
+
+	guards := RefreshGuards{MaxDropPercent: 10, MinEntities: 50, MinParseRatio: 0.95}
+	require.Nil(t, guards.check(list(95, 100), list(100, 100)))
+	require.Nil(t, guards.check(list(200, 200), nil))
+
+	failure := guards.check(list(10, 10), nil)
+	require.Equal(t, "min_entities", failure.guard)
+	require.Equal(t, "min_entities: found 10 entities, but at least 50 are required", failure.Error())
+
+	failure = guards.check(list(90, 100), nil)
+	require.Equal(t, "parse_ratio", failure.guard)
+
+	failure = guards.check(list(80, 80), list(100, 100))
+	require.Equal(t, "max_drop", failure.guard)
+	require.Equal(t, "max_drop: entities dropped 20.0% from 100 to 80, but at most 10.0% is allowed", failure.Error())
+}
+
+func TestDownloader_RefreshAll_Guards(t *testing.T) {
+	dir := t.TempDir()
+	where := filepath.Join(dir, "internal.csv")
+	require.NoError(t, os.WriteFile(where, []byte("name,type\nJohn Doe,person\nJane Doe,person\nAcme Corp,business\nWidgets LLC,business\n"), 0600))
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl"},
+		Guards: RefreshGuards{
+			MaxDropPercent: 50,
+		},
+		CustomLists: []CustomList{
+			{Name: "internal", Directory: dir},
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Lists["internal"])
+	require.Empty(t, stats.RejectedLists)
+
+	// A truncated file keeps the previous entities
+	require.NoError(t, os.WriteFile(where, []byte("name,type\nJohn Doe,person\n"), 0600))
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Lists["internal"])
+	require.Len(t, stats.Entities, 4)
+	require.Contains(t, stats.RejectedLists["internal"], "max_drop: entities dropped 75.0% from 4 to 1")
+
+	// Smaller changes are accepted and become the new baseline
+	require.NoError(t, os.WriteFile(where, []byte("name,type\nJohn Doe,person\nJane Doe,person\nAcme Corp,business\n"), 0600))
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Lists["internal"])
+	require.Empty(t, stats.RejectedLists)
+
+	// Rows which can't become entities count against MinParseRatio
+	conf.Guards = RefreshGuards{MinParseRatio: 0.9}
+	dl, err = NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(where, []byte("name,type\nJohn Doe,person\n,person\n"), 0600))
+
+	stats, err = dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, stats.Lists, "internal")
+	require.Contains(t, stats.RejectedLists["internal"], "parse_ratio: 1 of 2 records were parsed")
+}
+
+func TestDownloader_RefreshAll_MalformedRows(t *testing.T) {
+	dplContents, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+
+	// Keep the header and 20 rows, then cut half of those rows short
+	lines := strings.Split(string(dplContents), "\n")[:21]
+	for i := 2; i < len(lines); i += 2 {
+		lines[i] = strings.Join(strings.Split(lines[i], "\t")[:3], "\t")
+	}
+	where := filepath.Join(t.TempDir(), "dpl.txt")
+	require.NoError(t, os.WriteFile(where, []byte(strings.Join(lines, "\n")), 0600))
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {LocalFile: where},
+		},
+		Guards: RefreshGuards{
+			MinParseRatio: 0.9,
+		},
+	}
+	dl, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := dl.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.NotContains(t, stats.Lists, "us_dpl")
+	require.Contains(t, stats.RejectedLists["us_dpl"], "parse_ratio: 10 of 20 records were parsed")
+}
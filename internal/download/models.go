@@ -3,6 +3,7 @@ package download
 import (
 	"time"
 
+	pkgdownload "github.com/moov-io/watchman/pkg/download"
 	"github.com/moov-io/watchman/pkg/search"
 )
 
@@ -11,13 +12,89 @@ type Stats struct {
 
 	Lists map[string]int `json:"lists"`
 
+	// SkippedLists contains each list which was not refreshed along with the reason why
+	SkippedLists map[string]string `json:"skippedLists,omitempty"`
+
+	// RejectedLists contains each list whose refreshed data failed a RefreshGuards check along
+	// with the reason. The list's entities from its previous refresh are used instead.
+	RejectedLists map[string]string `json:"rejectedLists,omitempty"`
+
+	// Errors contains each list which failed to load. Their entities from the previous refresh are used instead.
+	Errors []ListError `json:"errors,omitempty"`
+
 	StartedAt time.Time `json:"startedAt"`
 	EndedAt   time.Time `json:"endedAt"`
 }
 
+type ListError struct {
+	List  string `json:"list"`
+	Error string `json:"error"`
+}
+
 type Config struct {
 	RefreshInterval      time.Duration
 	InitialDataDirectory string
 
-	DisabledLists []string // us_ofac, eu_csl, etc... // TODO(adam): check when we pull in other lists
+	// CacheDirectory keeps downloaded files along with their ETag and Last-Modified headers.
+	// Lists whose files haven't changed since their entities were last accepted aren't parsed again.
+	CacheDirectory string
+
+	// MaxListAge is the longest time since an enabled list was last loaded before the
+	// readiness check fails. Zero only requires each list to have been loaded.
+	MaxListAge time.Duration
+
+	// HTTP configures the client which lists are downloaded with
+	HTTP pkgdownload.HTTPConfig
+
+	DisabledLists []string // us_ofac, us_non_sdn, us_csl, eu_csl, uk_csl, uk_sanctions_list, un_csl, us_dpl, opensanctions
+
+	// Lists contains optional settings for each list, keyed by the same names as DisabledLists
+	Lists map[string]ListConfig
+
+	// Guards are checked against every list's refreshed data. Each list can override them in Lists.
+	Guards RefreshGuards
+
+	// CustomLists are internal watchlists which are read from local CSV or JSON files on each refresh
+	CustomLists []CustomList
+}
+
+type CustomList struct {
+	// Name is the SourceList of each entity. It's also used to refer to the list in DisabledLists and Lists.
+	Name string
+
+	// Directory contains the list's .csv, .json, .ndjson or .jsonl files
+	Directory string
+
+	// Columns maps entity fields to the column names used in the files.
+	// See the custom_list package for each field.
+	Columns map[string]string
+}
+
+type ListConfig struct {
+	// Enabled can be set to false to skip loading the list. uk_sanctions_list is only
+	// loaded when this is true or WITH_UK_SANCTIONS_LIST=true.
+	Enabled *bool
+
+	// RefreshInterval is how often the list is downloaded again. When it is zero the list
+	// is refreshed along with every other list.
+	RefreshInterval time.Duration
+
+	// DownloadURL replaces the default address files are downloaded from. A "%s" in the URL
+	// is replaced with the filename, which is required for lists made up of multiple files.
+	DownloadURL string
+
+	// LocalFile is read instead of downloading the list. It can be a single file or a
+	// directory containing each of the list's files.
+	LocalFile string
+
+	// Format selects an alternate file format for lists which publish more than one.
+	// us_ofac and us_non_sdn accept "csv" (default) or "xml" to read SDN_ADVANCED.XML
+	// or CONS_ADVANCED.XML respectively.
+	Format string
+
+	// Timeout replaces HTTP.Timeout for each request made downloading this list
+	Timeout time.Duration
+
+	// Guards replace the default RefreshGuards for this list
+	Guards RefreshGuards
 }
@@ -0,0 +1,489 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/csl_eu"
+	"github.com/moov-io/watchman/pkg/csl_uk"
+	"github.com/moov-io/watchman/pkg/csl_us"
+	"github.com/moov-io/watchman/pkg/custom_list"
+	pkgdownload "github.com/moov-io/watchman/pkg/download"
+	"github.com/moov-io/watchman/pkg/dpl"
+	"github.com/moov-io/watchman/pkg/ofac"
+	"github.com/moov-io/watchman/pkg/opensanctions"
+	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/sources"
+	"github.com/moov-io/watchman/pkg/un_csl"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/base/strx"
+)
+
+// listSource is a Source built from functions, which each built-in list uses
+type listSource struct {
+	name     string
+	files    []string
+	download downloadFunc
+	parse    func(files map[string]io.ReadCloser) (sources.List, error)
+	validate func(list sources.List) error
+
+	// disabledByDefault lists are only loaded when enabled in the config
+	disabledByDefault bool
+}
+
+func (s listSource) Name() string {
+	return s.name
+}
+
+func (s listSource) Files() []string {
+	return s.files
+}
+
+func (s listSource) Download(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+	return s.download(ctx, logger, initialDir, opts)
+}
+
+func (s listSource) Parse(files map[string]io.ReadCloser) (sources.List, error) {
+	return s.parse(files)
+}
+
+func (s listSource) Validate(list sources.List) error {
+	if s.validate == nil {
+		return nil
+	}
+	return s.validate(list)
+}
+
+var (
+	ofacFilenames         = []string{"ADD.CSV", "ALT.CSV", "SDN.CSV", "SDN_COMMENTS.CSV"}
+	consolidatedFilenames = []string{"CONS_ADD.CSV", "CONS_ALT.CSV", "CONS_PRIM.CSV", "CONS_COMMENTS.CSV"}
+)
+
+// builtinSources returns the lists included with Watchman in the order they are loaded
+func builtinSources(conf Config) []sources.Source {
+	ofacSource := listSource{
+		name:     "us_ofac",
+		files:    ofacFilenames,
+		download: requireOFACFiles(ofac.Download),
+		parse:    parseOFAC,
+		validate: requireEntities,
+	}
+	if strings.EqualFold(conf.Lists["us_ofac"].Format, "xml") {
+		ofacSource.files = []string{"SDN_ADVANCED.XML"}
+		ofacSource.download = requireOFACFiles(ofac.DownloadAdvanced)
+		ofacSource.parse = parseOFACAdvanced
+	}
+
+	nonSDNSource := listSource{
+		name:     "us_non_sdn",
+		files:    consolidatedFilenames,
+		download: requireOFACFiles(ofac.DownloadConsolidated),
+		parse:    parseOFACNonSDN,
+		validate: requireEntities,
+	}
+	if strings.EqualFold(conf.Lists["us_non_sdn"].Format, "xml") {
+		nonSDNSource.files = []string{"CONS_ADVANCED.XML"}
+		nonSDNSource.download = requireOFACFiles(ofac.DownloadConsolidatedAdvanced)
+		nonSDNSource.parse = parseOFACNonSDNAdvanced
+	}
+
+	return []sources.Source{
+		ofacSource,
+		nonSDNSource,
+		listSource{
+			name:     "eu_csl",
+			files:    []string{"eu_csl.csv"},
+			download: csl_eu.DownloadEU,
+			parse:    parseEUCSL,
+			validate: requireEntities,
+		},
+		listSource{
+			name:     "uk_csl",
+			files:    []string{"ConList.csv"},
+			download: csl_uk.DownloadCSL,
+			parse:    parseUKCSL,
+			validate: requireEntities,
+		},
+		listSource{
+			name:     "uk_sanctions_list",
+			files:    []string{"UK_Sanctions_List.ods"},
+			download: csl_uk.DownloadSanctionsList,
+			parse:    parseUKSanctionsList,
+			validate: requireEntities,
+			// opt-in, either with Lists.uk_sanctions_list.Enabled or the older environment variable
+			disabledByDefault: !strx.Yes(os.Getenv("WITH_UK_SANCTIONS_LIST")),
+		},
+		listSource{
+			name:     "us_csl",
+			files:    []string{"csl.csv"},
+			download: csl_us.Download,
+			parse:    parseUSCSL,
+			validate: requireEntities,
+		},
+		listSource{
+			name:     "un_csl",
+			files:    []string{"consolidated.xml"},
+			download: un_csl.Download,
+			parse:    parseUNCSL,
+			validate: requireEntities,
+		},
+		listSource{
+			name:     "us_dpl",
+			files:    []string{"dpl.txt"},
+			download: dpl.Download,
+			parse:    parseDPL,
+			validate: requireEntities,
+		},
+		listSource{
+			name:     "opensanctions",
+			download: downloadOpenSanctions(conf.Lists["opensanctions"].LocalFile),
+			parse:    parseOpenSanctions,
+		},
+	}
+}
+
+// customListSource reads a custom list's Directory on each refresh
+func customListSource(list CustomList) sources.Source {
+	return listSource{
+		name: list.Name,
+		download: func(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+			return readDirectory(list.Directory, []string{".csv", ".json", ".ndjson", ".jsonl"})
+		},
+		parse: func(files map[string]io.ReadCloser) (sources.List, error) {
+			return parseCustomList(list, files)
+		},
+	}
+}
+
+// sources returns the built-in lists, followed by each custom list and then each registered source
+func (c Config) sources(registered []sources.Source) []sources.Source {
+	out := builtinSources(c)
+	for _, list := range c.CustomLists {
+		out = append(out, customListSource(list))
+	}
+	return append(out, registered...)
+}
+
+// loadSource downloads, parses and validates a source's files
+// loadSource downloads and parses a list. acceptedHash is the ContentHash of the list's previous refresh, if any.
+func loadSource(ctx context.Context, logger log.Logger, conf Config, source sources.Source, opts pkgdownload.Options, acceptedHash string) ([]preparedList, error) {
+	name := source.Name()
+
+	start := time.Now()
+	files, err := downloadListFiles(ctx, logger, conf, name, source.Files(), source.Download, opts, acceptedHash)
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	if len(files) == 0 {
+		// Only lists without fixed files can be skipped, such as OpenSanctions without a LocalFile
+		logger.Info().Logf("skipping %s, no files were found", name)
+		return nil, nil
+	}
+
+	logger.Debug().Logf("finished %s download: %v", name, time.Since(start))
+	start = time.Now()
+
+	list, err := source.Parse(files)
+	hash := contentHash(files) // also closes each file
+	if err != nil {
+		return nil, err
+	}
+	if err := source.Validate(list); err != nil {
+		return nil, fmt.Errorf("invalid %s data: %w", name, err)
+	}
+	logger.Debug().Logf("finished %s preperation: %v", name, time.Since(start))
+
+	return []preparedList{{
+		ListName:    list.Source,
+		Entities:    list.Entities,
+		Records:     list.Records,
+		ContentHash: hash,
+		PublishedAt: list.PublishedAt,
+	}}, nil
+}
+
+// requireEntities rejects lists which were parsed without any entities
+func requireEntities(list sources.List) error {
+	if len(list.Entities) == 0 {
+		return errors.New("no entities found")
+	}
+	return nil
+}
+
+func requireFile(files map[string]io.ReadCloser, name string) (io.ReadCloser, error) {
+	file, exists := files[name]
+	if !exists {
+		return nil, fmt.Errorf("%s was not found", name)
+	}
+	return file, nil
+}
+
+// readDirectory opens each file within dir which has one of the extensions
+func readDirectory(dir string, extensions []string) (map[string]io.ReadCloser, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]io.ReadCloser)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.Type().IsRegular() || !slices.Contains(extensions, ext) {
+			continue
+		}
+		fd, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			for _, file := range out {
+				file.Close()
+			}
+			return nil, err
+		}
+		out[entry.Name()] = fd
+	}
+	return out, nil
+}
+
+func sortedFilenames(files map[string]io.ReadCloser) []string {
+	out := make([]string, 0, len(files))
+	for name := range files {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// requireOFACFiles fails when none of OFAC's files could be found or downloaded
+func requireOFACFiles(download downloadFunc) downloadFunc {
+	return func(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+		files, err := download(ctx, logger, initialDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("unexpected %d OFAC files found", len(files))
+		}
+		return files, nil
+	}
+}
+
+func parseOFAC(files map[string]io.ReadCloser) (sources.List, error) {
+	res, err := ofac.Read(files)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:   search.SourceUSOFAC,
+		Entities: ofac.GroupIntoEntities(res.SDNs, res.Addresses, res.SDNComments, res.AlternateIdentities),
+		Records:  len(res.SDNs) + res.SkippedSDNs,
+	}, nil
+}
+
+func parseOFACNonSDN(files map[string]io.ReadCloser) (sources.List, error) {
+	res, err := ofac.Read(files)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:   search.SourceUSNonSDN,
+		Entities: ofac.GroupConsolidatedIntoEntities(res.SDNs, res.Addresses, res.SDNComments, res.AlternateIdentities),
+		Records:  len(res.SDNs) + res.SkippedSDNs,
+	}, nil
+}
+
+func parseOFACAdvanced(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "SDN_ADVANCED.XML")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	doc, err := ofac.ReadAdvanced(file)
+	if err != nil {
+		return sources.List{}, fmt.Errorf("reading OFAC advanced: %w", err)
+	}
+	return sources.List{
+		Source:      search.SourceUSOFAC,
+		Entities:    ofac.GroupAdvancedIntoEntities(doc),
+		Records:     len(doc.DistinctParties),
+		PublishedAt: publishedDate(doc.DateOfIssue.Year, doc.DateOfIssue.Month, doc.DateOfIssue.Day),
+	}, nil
+}
+
+func parseOFACNonSDNAdvanced(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "CONS_ADVANCED.XML")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	doc, err := ofac.ReadAdvanced(file)
+	if err != nil {
+		return sources.List{}, fmt.Errorf("reading OFAC consolidated advanced: %w", err)
+	}
+	return sources.List{
+		Source:      search.SourceUSNonSDN,
+		Entities:    ofac.GroupConsolidatedAdvancedIntoEntities(doc),
+		Records:     len(doc.DistinctParties),
+		PublishedAt: publishedDate(doc.DateOfIssue.Year, doc.DateOfIssue.Month, doc.DateOfIssue.Day),
+	}, nil
+}
+
+func parseEUCSL(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "eu_csl.csv")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	records, skipped, err := csl_eu.ParseEUWithSkipped(file)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:      search.SourceEUCSL,
+		Entities:    csl_eu.ConvertSanctionsData(records),
+		Records:     len(records) + skipped,
+		PublishedAt: euPublishedAt(records),
+	}, nil
+}
+
+func parseUKCSL(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "ConList.csv")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	records, skipped, err := csl_uk.ReadCSLFileWithSkipped(file)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:   search.SourceUKCSL,
+		Entities: csl_uk.ConvertCSLData(records),
+		Records:  len(records) + skipped,
+	}, nil
+}
+
+func parseUKSanctionsList(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "UK_Sanctions_List.ods")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	records, skipped, err := csl_uk.ReadSanctionsListFileWithSkipped(file)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:   search.SourceUKSanctionsList,
+		Entities: csl_uk.ConvertSanctionsListData(records),
+		Records:  len(records) + skipped,
+	}, nil
+}
+
+func parseUSCSL(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "csl.csv")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	records, err := csl_us.ReadFile(file)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:   search.SourceUSCSL,
+		Entities: csl_us.ConvertSanctionsData(records),
+		Records:  records.Len() + records.Skipped,
+	}, nil
+}
+
+func parseUNCSL(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "consolidated.xml")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	list, err := un_csl.ReadFile(file)
+	if err != nil {
+		return sources.List{}, err
+	}
+	return sources.List{
+		Source:      search.SourceUNCSL,
+		Entities:    un_csl.ConvertSanctionsData(list),
+		Records:     len(list.Individuals) + len(list.Entities),
+		PublishedAt: parsePublishedAt(time.RFC3339, list.DateGenerated),
+	}, nil
+}
+
+func parseDPL(files map[string]io.ReadCloser) (sources.List, error) {
+	file, err := requireFile(files, "dpl.txt")
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	records, skipped, err := dpl.ReadFileWithSkipped(file)
+	if err != nil {
+		return sources.List{}, fmt.Errorf("reading US DPL: %w", err)
+	}
+	return sources.List{
+		Source:   search.SourceUSDPL,
+		Entities: dpl.ConvertSanctionsData(records),
+		Records:  len(records) + skipped,
+	}, nil
+}
+
+// downloadOpenSanctions reads the exported files from the list's LocalFile
+func downloadOpenSanctions(localFile string) downloadFunc {
+	return func(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+		if localFile == "" {
+			// OpenSanctions exports are only read from a local directory
+			logger.Debug().Log("skipping OpenSanctions, no LocalFile configured")
+			return nil, nil
+		}
+		return readDirectory(initialDir, []string{".json", ".ndjson", ".jsonl"})
+	}
+}
+
+func parseOpenSanctions(files map[string]io.ReadCloser) (sources.List, error) {
+	var records []opensanctions.Entity
+	for _, name := range sortedFilenames(files) {
+		entities, err := opensanctions.ReadFile(files[name])
+		if err != nil {
+			return sources.List{}, fmt.Errorf("%s: %w", name, err)
+		}
+		records = append(records, entities...)
+	}
+	return sources.List{
+		Source:   search.SourceOpenSanctions,
+		Entities: opensanctions.ConvertEntities(records),
+	}, nil
+}
+
+func parseCustomList(list CustomList, files map[string]io.ReadCloser) (sources.List, error) {
+	var records []custom_list.Record
+	for _, name := range sortedFilenames(files) {
+		read := custom_list.ReadCSV
+		if strings.ToLower(filepath.Ext(name)) != ".csv" {
+			read = custom_list.ReadJSON
+		}
+		found, err := read(name, files[name])
+		if err != nil {
+			return sources.List{}, fmt.Errorf("custom list %s: %w", name, err)
+		}
+		records = append(records, found...)
+	}
+
+	source := search.SourceList(list.Name)
+	return sources.List{
+		Source:   source,
+		Entities: custom_list.ConvertRecords(source, list.Columns, records),
+		Records:  len(records),
+	}, nil
+}
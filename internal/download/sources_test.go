@@ -0,0 +1,186 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	pkgdownload "github.com/moov-io/watchman/pkg/download"
+	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/sources"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+// namesSource has an entity for each line of names.txt
+type namesSource struct {
+	names   string
+	err     error
+	missing bool
+}
+
+func (s *namesSource) Name() string    { return "test_names" }
+func (s *namesSource) Files() []string { return []string{"names.txt"} }
+
+func (s *namesSource) Download(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+	if s.missing {
+		return map[string]io.ReadCloser{}, nil
+	}
+	return map[string]io.ReadCloser{
+		"names.txt": io.NopCloser(strings.NewReader(s.names)),
+	}, nil
+}
+
+func (s *namesSource) Parse(files map[string]io.ReadCloser) (sources.List, error) {
+	bs, err := io.ReadAll(files["names.txt"])
+	if err != nil {
+		return sources.List{}, err
+	}
+
+	list := sources.List{Source: "test_names"}
+	for _, name := range strings.Split(strings.TrimSpace(string(bs)), "\n") {
+		list.Entities = append(list.Entities, search.Entity[search.Value]{
+			Name:     name,
+			Type:     search.EntityPerson,
+			Source:   list.Source,
+			SourceID: name,
+		})
+		list.Records++
+	}
+	return list, nil
+}
+
+func (s *namesSource) Validate(list sources.List) error {
+	return s.err
+}
+
+func TestDownloader_RegisteredSources(t *testing.T) {
+	source := &namesSource{names: "John Doe\nJane Doe\n"}
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl", "opensanctions"},
+	}
+	d, err := newDownloader(log.NewTestLogger(), conf, []sources.Source{source})
+	require.NoError(t, err)
+
+	stats, err := d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists["test_names"])
+
+	status := findStatus(t, d.Status(), "test_names")
+	require.True(t, status.Enabled)
+	require.Len(t, status.ContentHash, 64)
+
+	// a list which fails validation keeps its previous entities
+	source.names = "John Doe\n"
+	source.err = errors.New("too short")
+
+	stats, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists["test_names"])
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, "invalid test_names data: too short", stats.Errors[0].Error)
+
+	// so does a list whose files are missing
+	source.err = nil
+	source.missing = true
+
+	stats, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.Lists["test_names"])
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, "download: missing names.txt", stats.Errors[0].Error)
+	source.missing = false
+
+	// registered sources are configured like any other list
+	conf.DisabledLists = append(conf.DisabledLists, "test_names")
+	d, err = newDownloader(log.NewTestLogger(), conf, []sources.Source{source})
+	require.NoError(t, err)
+
+	stats, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "disabled", stats.SkippedLists["test_names"])
+
+	_, err = newDownloader(log.NewTestLogger(), Config{CustomLists: []CustomList{{Name: "test_names", Directory: "/tmp"}}}, []sources.Source{source})
+	require.ErrorContains(t, err, "source test_names: name is already used")
+}
+
+func TestDownloader_Restore(t *testing.T) {
+	source := &namesSource{err: errors.New("unavailable")}
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl", "opensanctions"},
+		Guards: RefreshGuards{
+			MaxDropPercent: 50,
+		},
+	}
+	d, err := newDownloader(log.NewTestLogger(), conf, []sources.Source{source})
+	require.NoError(t, err)
+
+	var snapshot []search.Entity[search.Value]
+	for _, name := range []string{"John Doe", "Jane Doe", "Jim Doe"} {
+		snapshot = append(snapshot, search.Entity[search.Value]{Name: name, Source: "test_names", SourceID: name})
+	}
+	snapshot = append(snapshot, search.Entity[search.Value]{Name: "Acme Corp", Source: search.SourceUSOFAC, SourceID: "1"})
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	d.Restore(createdAt, snapshot)
+
+	// the snapshot's lists are reported as loaded before the first refresh
+	require.NoError(t, d.Ready())
+	for _, status := range d.Status() {
+		if status.Name != "test_names" {
+			require.False(t, status.Enabled)
+			require.Nil(t, status.LastSuccess)
+			continue
+		}
+		require.True(t, status.Enabled)
+		require.Equal(t, 3, status.Entities)
+		require.Equal(t, createdAt, *status.LastSuccess)
+	}
+
+	// a list which fails its first refresh keeps the snapshot's entities
+	stats, err := d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats.Errors, 1)
+	require.Equal(t, 3, stats.Lists["test_names"])
+	require.NotContains(t, stats.Lists, string(search.SourceUSOFAC))
+
+	// refresh guards compare against the snapshot
+	source.names = "John Doe\n"
+	source.err = nil
+
+	stats, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Lists["test_names"])
+	require.Contains(t, stats.RejectedLists["test_names"], "max_drop")
+}
+
+func TestBuiltinSources(t *testing.T) {
+	var names []string
+	for _, source := range builtinSources(Config{}) {
+		names = append(names, source.Name())
+	}
+	require.Equal(t, []string{"us_ofac", "us_non_sdn", "eu_csl", "uk_csl", "uk_sanctions_list", "us_csl", "un_csl", "us_dpl", "opensanctions"}, names)
+
+	// OFAC reads its advanced XML file when configured
+	conf := Config{Lists: map[string]ListConfig{"us_ofac": {Format: "XML"}}}
+	require.Equal(t, []string{"SDN_ADVANCED.XML"}, builtinSources(conf)[0].Files())
+
+	conf = Config{Lists: map[string]ListConfig{"us_non_sdn": {Format: "xml"}}}
+	require.Equal(t, []string{"ADD.CSV", "ALT.CSV", "SDN.CSV", "SDN_COMMENTS.CSV"}, builtinSources(conf)[0].Files())
+	require.Equal(t, []string{"CONS_ADVANCED.XML"}, builtinSources(conf)[1].Files())
+
+	// empty lists are rejected
+	require.ErrorContains(t, builtinSources(Config{})[0].Validate(sources.List{}), "no entities found")
+
+	// OFAC fails without any files
+	download := requireOFACFiles(func(ctx context.Context, logger log.Logger, initialDir string, opts pkgdownload.Options) (map[string]io.ReadCloser, error) {
+		return nil, nil
+	})
+	_, err := download(context.Background(), log.NewTestLogger(), "", pkgdownload.Options{})
+	require.EqualError(t, err, "unexpected 0 OFAC files found")
+}
@@ -0,0 +1,216 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/csl_eu"
+)
+
+// ListStatus describes the most recent refreshes of a list
+type ListStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+
+	// Entities is how many entities from the list are being searched
+	Entities int `json:"entities"`
+
+	LastAttempt *time.Time `json:"lastAttempt,omitempty"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	Duration    string     `json:"duration,omitempty"`
+
+	// Errors are from the last attempt. The entities from LastSuccess are used while there are errors.
+	Errors []string `json:"errors,omitempty"`
+
+	// Skipped is why the list was not loaded on the last refresh
+	Skipped string `json:"skipped,omitempty"`
+
+	// ContentHash is the SHA-256 of the list's file. For lists made up of multiple files
+	// it's the SHA-256 of each filename and file hash, sorted by filename.
+	ContentHash string `json:"contentHash,omitempty"`
+
+	// PublishedAt is when the list's publisher generated the data, for lists whose files contain it
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+}
+
+// Status returns every list in the order they are loaded
+func (dl *downloader) Status() []ListStatus {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	out := make([]ListStatus, 0, len(dl.sources))
+	for _, source := range dl.sources {
+		status, exists := dl.status[source.Name()]
+		if !exists {
+			status = ListStatus{
+				Name:    source.Name(),
+				Enabled: dl.conf.listEnabled(source),
+			}
+		}
+		status.Errors = append([]string(nil), status.Errors...)
+		out = append(out, status)
+	}
+	return out
+}
+
+// Ready returns an error when an enabled list hasn't been loaded or was last loaded longer than MaxListAge ago.
+func (dl *downloader) Ready() error {
+	now := time.Now()
+
+	var problems []string
+	for _, status := range dl.Status() {
+		if !status.Enabled {
+			continue
+		}
+		if status.LastSuccess == nil {
+			problems = append(problems, fmt.Sprintf("%s has not been loaded", status.Name))
+			continue
+		}
+		if age := now.Sub(*status.LastSuccess); dl.conf.MaxListAge > 0 && age > dl.conf.MaxListAge {
+			problems = append(problems, fmt.Sprintf("%s was last loaded %v ago", status.Name, age.Truncate(time.Second)))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+func (dl *downloader) updateStatus(name string, update func(status *ListStatus)) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	status, exists := dl.status[name]
+	if !exists {
+		status = ListStatus{Name: name}
+	}
+	update(&status)
+	dl.status[name] = status
+}
+
+// listDetails returns the entity count, content hash and publish date from a list's prepared data
+func listDetails(lists []preparedList) (int, string, *time.Time) {
+	var entities int
+	var contentHash string
+	var publishedAt *time.Time
+	for _, list := range lists {
+		entities += len(list.Entities)
+		if contentHash == "" {
+			contentHash = list.ContentHash
+		}
+		if publishedAt == nil {
+			publishedAt = list.PublishedAt
+		}
+	}
+	return entities, contentHash, publishedAt
+}
+
+func publishedDate(year, month, day int) *time.Time {
+	if year <= 0 || month <= 0 || day <= 0 {
+		return nil
+	}
+	tt := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return &tt
+}
+
+func parsePublishedAt(layout, value string) *time.Time {
+	tt, err := time.Parse(layout, strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	tt = tt.In(time.UTC)
+	return &tt
+}
+
+// euPublishedAt reads the file generation date, which is repeated on every row
+func euPublishedAt(records []csl_eu.CSLRecord) *time.Time {
+	for _, record := range records {
+		if record.FileGenerationDate != "" {
+			return parsePublishedAt("02/01/2006", record.FileGenerationDate)
+		}
+	}
+	return nil
+}
+
+// hashingFile computes the SHA-256 of everything read from a file
+type hashingFile struct {
+	io.ReadCloser
+
+	hash   hash.Hash
+	reader io.Reader
+	sum    string
+}
+
+func newHashingFile(file io.ReadCloser) *hashingFile {
+	h := sha256.New()
+	return &hashingFile{
+		ReadCloser: file,
+		hash:       h,
+		reader:     io.TeeReader(file, h),
+	}
+}
+
+func (f *hashingFile) Read(p []byte) (int, error) {
+	return f.reader.Read(p)
+}
+
+// Close reads whatever the parser left unread so the hash covers the whole file
+func (f *hashingFile) Close() error {
+	if f.sum == "" {
+		io.Copy(io.Discard, f.reader)
+		f.sum = hex.EncodeToString(f.hash.Sum(nil))
+	}
+	return f.ReadCloser.Close()
+}
+
+func hashFiles(files map[string]io.ReadCloser) map[string]io.ReadCloser {
+	out := make(map[string]io.ReadCloser, len(files))
+	for name, file := range files {
+		out[name] = newHashingFile(file)
+	}
+	return out
+}
+
+// contentHash closes each file and returns their combined hash
+func contentHash(files map[string]io.ReadCloser) string {
+	sums := make(map[string]string)
+	for name, file := range files {
+		hf, ok := file.(*hashingFile)
+		if !ok {
+			continue
+		}
+		hf.Close()
+		sums[name] = hf.sum
+	}
+	return combineHashes(sums)
+}
+
+func combineHashes(sums map[string]string) string {
+	switch len(sums) {
+	case 0:
+		return ""
+	case 1:
+		for _, sum := range sums {
+			return sum
+		}
+	}
+
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", name, sums[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,181 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloader_Status(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "opensanctions"},
+		Lists: map[string]ListConfig{
+			"us_dpl": {
+				LocalFile: filepath.Join("..", "..", "test", "testdata", "dpl.txt"),
+			},
+		},
+		CustomLists: []CustomList{
+			{Name: "internal", Directory: filepath.Join("..", "..", "pkg", "custom_list", "testdata", "fraud")},
+		},
+	}
+	d, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	// nothing is ready before the first refresh
+	require.ErrorContains(t, d.Ready(), "us_dpl has not been loaded, internal has not been loaded")
+
+	statuses := d.Status()
+	require.Len(t, statuses, len(builtinSources(Config{}))+1)
+	require.Equal(t, "us_ofac", statuses[0].Name)
+	require.False(t, statuses[0].Enabled)
+
+	_, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, d.Ready())
+
+	dplContents, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+	dplHash := sha256.Sum256(dplContents)
+
+	statuses = d.Status()
+	dpl := findStatus(t, statuses, "us_dpl")
+	require.True(t, dpl.Enabled)
+	require.Equal(t, 546, dpl.Entities)
+	require.NotNil(t, dpl.LastAttempt)
+	require.Equal(t, dpl.LastAttempt, dpl.LastSuccess)
+	require.NotEmpty(t, dpl.Duration)
+	require.Empty(t, dpl.Errors)
+	require.Equal(t, hex.EncodeToString(dplHash[:]), dpl.ContentHash)
+
+	internal := findStatus(t, statuses, "internal")
+	require.Equal(t, 3, internal.Entities)
+	require.Len(t, internal.ContentHash, 64)
+
+	ofac := findStatus(t, statuses, "us_ofac")
+	require.Equal(t, "disabled", ofac.Skipped)
+
+	// data older than MaxListAge isn't ready
+	dl, ok := d.(*downloader)
+	require.True(t, ok)
+	dl.conf.MaxListAge = time.Minute
+	require.NoError(t, d.Ready())
+
+	dl.updateStatus("us_dpl", func(status *ListStatus) {
+		old := time.Now().Add(-2 * time.Hour)
+		status.LastSuccess = &old
+	})
+	require.ErrorContains(t, d.Ready(), "us_dpl was last loaded 2h0m0s ago")
+}
+
+func TestDownloader_Status_Failure(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal.csv"), []byte("name\nJohn Doe\n"), 0600))
+
+	conf := Config{
+		DisabledLists: []string{"us_ofac", "us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "un_csl", "us_dpl", "opensanctions"},
+		CustomLists: []CustomList{
+			{Name: "internal", Directory: dir},
+		},
+	}
+	d, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	_, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	first := findStatus(t, d.Status(), "internal")
+
+	require.NoError(t, os.RemoveAll(dir))
+	_, err = d.RefreshAll(context.Background())
+	require.NoError(t, err)
+
+	// the previous entities and success are kept
+	second := findStatus(t, d.Status(), "internal")
+	require.Equal(t, 1, second.Entities)
+	require.Equal(t, first.LastSuccess, second.LastSuccess)
+	require.True(t, second.LastAttempt.After(*first.LastAttempt))
+	require.Len(t, second.Errors, 1)
+	require.Equal(t, first.ContentHash, second.ContentHash)
+}
+
+func findStatus(t *testing.T, statuses []ListStatus, name string) ListStatus {
+	t.Helper()
+
+	for _, status := range statuses {
+		if status.Name == name {
+			return status
+		}
+	}
+	t.Fatalf("%s not found", name)
+	return ListStatus{}
+}
+
+func TestContentHash(t *testing.T) {
+	sum := func(s string) string {
+		bs := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(bs[:])
+	}
+
+	// files which are partially read are still fully hashed
+	files := hashFiles(map[string]io.ReadCloser{
+		"a.csv": io.NopCloser(strings.NewReader("hello, world")),
+	})
+	buf := make([]byte, 5)
+	_, err := files["a.csv"].Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, sum("hello, world"), contentHash(files))
+
+	files = hashFiles(map[string]io.ReadCloser{
+		"b.csv": io.NopCloser(strings.NewReader("two")),
+		"a.csv": io.NopCloser(strings.NewReader("one")),
+	})
+	expected := sum("a.csv " + sum("one") + "\nb.csv " + sum("two") + "\n")
+	require.Equal(t, expected, contentHash(files))
+}
+
+func TestPublishedAt(t *testing.T) {
+	require.Nil(t, publishedDate(0, 0, 0))
+	require.Equal(t, time.Date(2024, time.November, 5, 0, 0, 0, 0, time.UTC), *publishedDate(2024, 11, 5))
+
+	published := parsePublishedAt(time.RFC3339, "2024-11-05T16:01:03.453-05:00")
+	require.Equal(t, "2024-11-05T21:01:03.453Z", published.Format(time.RFC3339Nano))
+	require.Nil(t, parsePublishedAt(time.RFC3339, ""))
+}
+
+func TestDownloader_RefreshAll_PublishedAt(t *testing.T) {
+	conf := Config{
+		DisabledLists: []string{"us_non_sdn", "us_csl", "eu_csl", "uk_csl", "uk_sanctions_list", "us_dpl", "opensanctions"},
+		Lists: map[string]ListConfig{
+			"us_ofac": {
+				Format:    "xml",
+				LocalFile: filepath.Join("..", "..", "pkg", "ofac", "testdata", "sdn_advanced.xml"),
+			},
+			"un_csl": {
+				LocalFile: filepath.Join("..", "..", "pkg", "un_csl", "testdata", "consolidated.xml"),
+			},
+		},
+	}
+	d, err := NewDownloader(log.NewTestLogger(), conf)
+	require.NoError(t, err)
+
+	stats, err := d.RefreshAll(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, stats.Lists[string(search.SourceUNCSL)])
+
+	statuses := d.Status()
+	ofac := findStatus(t, statuses, "us_ofac")
+	require.Equal(t, "2024-11-05", ofac.PublishedAt.Format("2006-01-02"))
+
+	un := findStatus(t, statuses, "un_csl")
+	require.Equal(t, "2024-11-05T21:01:03Z", un.PublishedAt.Format(time.RFC3339))
+}
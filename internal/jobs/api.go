@@ -0,0 +1,256 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/custom_list"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+)
+
+type Controller interface {
+	AppendRoutes(router *mux.Router) *mux.Router
+}
+
+func NewController(logger log.Logger, manager *Manager) Controller {
+	return &controller{
+		logger:  logger,
+		manager: manager,
+	}
+}
+
+type controller struct {
+	logger  log.Logger
+	manager *Manager
+}
+
+const (
+	// transferTimeout replaces the server's timeouts while uploading files and downloading results
+	transferTimeout = 10 * time.Minute
+
+	// maxFormMemory is how much of an upload is kept in memory, the rest is written to temporary files
+	maxFormMemory = 10 * 1024 * 1024
+)
+
+func (c *controller) AppendRoutes(router *mux.Router) *mux.Router {
+	router.
+		Name("CreateJob.v2").
+		Methods("POST").
+		Path("/v2/jobs").
+		HandlerFunc(c.createJob)
+
+	router.
+		Name("GetJob.v2").
+		Methods("GET").
+		Path("/v2/jobs/{jobID}").
+		HandlerFunc(c.getJob)
+
+	router.
+		Name("GetJobResults.v2").
+		Methods("GET").
+		Path("/v2/jobs/{jobID}/results").
+		HandlerFunc(c.getResults)
+
+	router.
+		Name("DeleteJob.v2").
+		Methods("DELETE").
+		Path("/v2/jobs/{jobID}").
+		HandlerFunc(c.deleteJob)
+
+	return router
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{
+		Error: err.Error(),
+	})
+}
+
+func (c *controller) writeManagerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrDisabled), errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrNotFinished):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrQueueFull):
+		writeError(w, http.StatusTooManyRequests, err)
+	case errors.Is(err, ErrInvalidUpload):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		c.logger.Error().LogErrorf("problem with job: %v", err)
+		writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+	}
+}
+
+func writeJob(w http.ResponseWriter, status int, job Job) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (c *controller) createJob(w http.ResponseWriter, r *http.Request) {
+	if !c.manager.Enabled() {
+		writeError(w, http.StatusNotFound, ErrDisabled)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Now().Add(transferTimeout)) //nolint:errcheck
+
+	// Leave room for the form's other fields
+	r.Body = http.MaxBytesReader(w, r.Body, c.manager.maxUploadSize()+maxFormMemory)
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("uploads are limited to %d bytes", c.manager.maxUploadSize()))
+			return
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem reading upload: %w", err))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem reading file: %w", err))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > c.manager.maxUploadSize() {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("uploads are limited to %d bytes", c.manager.maxUploadSize()))
+		return
+	}
+
+	opts, err := readOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := c.manager.Create(header.Filename, file, opts)
+	if err != nil {
+		c.writeManagerError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v2/jobs/%s", job.ID))
+	writeJob(w, http.StatusAccepted, job)
+}
+
+// readOptions reads the columns, limit and minMatch from the form or query string
+func readOptions(r *http.Request) (Options, error) {
+	var opts Options
+
+	if v := strings.TrimSpace(r.FormValue("columns")); v != "" {
+		var columns custom_list.Columns
+		if err := json.Unmarshal([]byte(v), &columns); err != nil {
+			return opts, fmt.Errorf("invalid columns: %w", err)
+		}
+		opts.Columns = columns
+	}
+
+	if v := strings.TrimSpace(r.FormValue("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid limit: %w", err)
+		}
+		opts.Limit = n
+	}
+
+	if v := strings.TrimSpace(r.FormValue("minMatch")); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, fmt.Errorf("invalid minMatch: %w", err)
+		}
+		opts.MinMatch = n
+	}
+
+	return opts, nil
+}
+
+func (c *controller) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := c.manager.Get(mux.Vars(r)["jobID"])
+	if err != nil {
+		c.writeManagerError(w, err)
+		return
+	}
+	writeJob(w, http.StatusOK, job)
+}
+
+func (c *controller) getResults(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["jobID"]
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "ndjson"
+	}
+	if format != "ndjson" && format != "csv" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown format %q, expected csv or ndjson", format))
+		return
+	}
+
+	results, err := c.manager.Results(jobID)
+	if err != nil {
+		c.writeManagerError(w, err)
+		return
+	}
+	defer results.Close()
+
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Now().Add(transferTimeout)) //nolint:errcheck
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, jobID, format))
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		err = writeCSV(w, results)
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		_, err = results.WriteTo(w)
+	}
+	if err != nil {
+		c.logger.Warn().LogErrorf("problem writing results for job %s: %v", jobID, err)
+	}
+}
+
+// deleteJob cancels a job which hasn't finished, otherwise it removes the job and its results
+func (c *controller) deleteJob(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["jobID"]
+
+	job, err := c.manager.Get(jobID)
+	if err != nil {
+		c.writeManagerError(w, err)
+		return
+	}
+
+	if !job.Finished() {
+		job, err = c.manager.Cancel(jobID)
+		if err != nil {
+			c.writeManagerError(w, err)
+			return
+		}
+		writeJob(w, http.StatusOK, job)
+		return
+	}
+
+	if err := c.manager.Delete(jobID); err != nil {
+		c.writeManagerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func uploadRequest(t *testing.T, fields map[string]string, file string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, mw.WriteField(name, value))
+	}
+	fw, err := mw.CreateFormFile("file", "customers.csv")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(file))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/v2/jobs", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestController_Jobs(t *testing.T) {
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: t.TempDir()}, testService(t))
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	m.Start(ctx)
+
+	router := NewController(log.NewTestLogger(), m).AppendRoutes(mux.NewRouter())
+
+	req := uploadRequest(t, map[string]string{
+		"columns": `{"sourceID": "Customer ID", "name": "Full Name", "type": "Kind"}`,
+		"limit":   "1",
+	}, customers)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	var job Job
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+	require.Equal(t, "/v2/jobs/"+job.ID, w.Header().Get("Location"))
+	require.Equal(t, "customers.csv", job.Filename)
+	require.Equal(t, 1, job.Options.Limit)
+
+	waitFor(t, m, job.ID, StatusCompleted)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"completed"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID+"/results", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var results []Result
+	dec := json.NewDecoder(w.Body)
+	for dec.More() {
+		var result Result
+		require.NoError(t, dec.Decode(&result))
+		results = append(results, result)
+	}
+	require.Len(t, results, 3)
+	require.Equal(t, "c1", results[0].ReferenceID)
+	require.Len(t, results[0].Entities, 1)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID+"/results?format=csv", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	require.True(t, strings.HasPrefix(w.Body.String(), "row,referenceID,name,"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID+"/results?format=xml", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v2/jobs/"+job.ID, nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID, nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestController_JobErrors(t *testing.T) {
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: t.TempDir(), MaxUploadSize: 64}, testService(t))
+	require.NoError(t, err)
+	router := NewController(log.NewTestLogger(), m).AppendRoutes(mux.NewRouter())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, uploadRequest(t, nil, "Full Name\nJohn Doe\n"))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "name")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, uploadRequest(t, map[string]string{"limit": "ten"}, "name\nJohn Doe\n"))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, uploadRequest(t, nil, "name\n"+strings.Repeat("John Doe\n", 10)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// jobs which haven't finished can be canceled, but have no results
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, uploadRequest(t, nil, "name\nJohn Doe\n"))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var job Job
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/"+job.ID+"/results", nil))
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/v2/jobs/"+job.ID, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"canceled"`)
+
+	// jobs are disabled without a directory
+	disabled, err := NewManager(log.NewTestLogger(), Config{}, testService(t))
+	require.NoError(t, err)
+	router = NewController(log.NewTestLogger(), disabled).AppendRoutes(mux.NewRouter())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/v2/jobs/123", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), "jobs are disabled")
+}
@@ -0,0 +1,634 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	searcher "github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/pkg/custom_list"
+
+	"github.com/moov-io/base/log"
+)
+
+type Config struct {
+	// Directory keeps each job's uploaded file and results. Jobs are disabled when empty.
+	Directory string
+
+	// Workers is how many jobs are run at once. Defaults to 2.
+	Workers int
+
+	// MaxQueued is how many jobs can wait for a worker. Defaults to 100.
+	MaxQueued int
+
+	// MaxUploadSize is the largest file (in bytes) which can be uploaded. Defaults to 100MB.
+	MaxUploadSize int64
+
+	// Retention is how long finished jobs and their results are kept. Defaults to 7 days.
+	Retention time.Duration
+}
+
+const (
+	defaultWorkers       = 2
+	defaultMaxQueued     = 100
+	defaultMaxUploadSize = 100 * 1024 * 1024
+	defaultRetention     = 7 * 24 * time.Hour
+
+	cleanupInterval = time.Hour
+
+	softResultsLimit, hardResultsLimit = 10, 100
+
+	jobFilename     = "job.json"
+	uploadFilename  = "upload.csv"
+	resultsFilename = "results.ndjson"
+)
+
+var (
+	ErrDisabled      = errors.New("jobs are disabled")
+	ErrNotFound      = errors.New("job not found")
+	ErrNotFinished   = errors.New("job has not completed")
+	ErrQueueFull     = errors.New("too many jobs are queued")
+	ErrInvalidUpload = errors.New("invalid upload")
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Options are how each row of a job is searched
+type Options struct {
+	// Columns maps entity fields to the file's column names, the same as custom lists
+	Columns custom_list.Columns `json:"columns,omitempty"`
+
+	Limit    int     `json:"limit"`
+	MinMatch float64 `json:"minMatch"`
+}
+
+// Job screens each row of an uploaded CSV file
+type Job struct {
+	ID       string  `json:"id"`
+	Status   Status  `json:"status"`
+	Filename string  `json:"filename"`
+	Options  Options `json:"options"`
+
+	// Rows is how many rows the file has, which is known once the job starts
+	Rows      int `json:"rows"`
+	Processed int `json:"processed"`
+	Matched   int `json:"matched"` // rows with at least one match
+
+	Error string `json:"error,omitempty"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Finished returns true once a job won't change
+func (j Job) Finished() bool {
+	switch j.Status {
+	case StatusCompleted, StatusFailed, StatusCanceled:
+		return true
+	}
+	return false
+}
+
+// Manager runs jobs with a fixed number of workers and keeps their files in Directory.
+// Jobs which were queued or running when the process stopped are queued again on startup.
+type Manager struct {
+	logger  log.Logger
+	conf    Config
+	service searcher.Service
+
+	wake chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	// reserved is how many jobs are saving their upload, which count towards MaxQueued
+	reserved int
+}
+
+type job struct {
+	Job
+	cancel context.CancelFunc
+}
+
+func NewManager(logger log.Logger, conf Config, service searcher.Service) (*Manager, error) {
+	m := &Manager{
+		logger:  logger,
+		conf:    conf,
+		service: service,
+		wake:    make(chan struct{}, 1),
+		jobs:    make(map[string]*job),
+	}
+	if !m.Enabled() {
+		return m, nil
+	}
+
+	if err := os.MkdirAll(conf.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("creating jobs directory: %w", err)
+	}
+	if err := m.load(); err != nil {
+		return nil, fmt.Errorf("loading jobs: %w", err)
+	}
+	return m, nil
+}
+
+// Enabled returns true when a Directory is configured
+func (m *Manager) Enabled() bool {
+	return m != nil && m.conf.Directory != ""
+}
+
+// Start runs queued jobs and removes expired jobs until ctx is done.
+// Running jobs are stopped and queued again when ctx is done.
+func (m *Manager) Start(ctx context.Context) {
+	if !m.Enabled() {
+		return
+	}
+
+	workers := m.conf.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	for i := 0; i < workers; i++ {
+		go m.work(ctx)
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			m.removeExpired(time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Create saves the uploaded file and queues a job to search each of its rows
+func (m *Manager) Create(filename string, file io.Reader, opts Options) (Job, error) {
+	if !m.Enabled() {
+		return Job{}, ErrDisabled
+	}
+
+	// Reserve a place in the queue while the upload is saved
+	m.mu.Lock()
+	queued := m.reserved
+	for _, j := range m.jobs {
+		if j.Status == StatusQueued {
+			queued++
+		}
+	}
+	if queued >= m.maxQueued() {
+		m.mu.Unlock()
+		return Job{}, ErrQueueFull
+	}
+	m.reserved++
+	m.mu.Unlock()
+
+	added := false
+	defer func() {
+		if !added {
+			m.mu.Lock()
+			m.reserved--
+			m.mu.Unlock()
+		}
+	}()
+
+	if opts.Limit <= 0 {
+		opts.Limit = softResultsLimit
+	}
+	if opts.Limit > hardResultsLimit {
+		opts.Limit = hardResultsLimit
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	j := &job{
+		Job: Job{
+			ID:        id,
+			Status:    StatusQueued,
+			Filename:  filepath.Base(filename),
+			Options:   opts,
+			CreatedAt: time.Now().In(time.UTC),
+		},
+	}
+
+	dir := m.jobDir(j.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Job{}, fmt.Errorf("creating job directory: %w", err)
+	}
+	err = saveUpload(filepath.Join(dir, uploadFilename), file, opts.Columns)
+	if err == nil {
+		err = m.save(j.Job)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return Job{}, err
+	}
+
+	m.mu.Lock()
+	m.reserved--
+	m.jobs[j.ID] = j
+	out := j.Job
+	m.mu.Unlock()
+	added = true
+
+	m.notify()
+
+	return out, nil
+}
+
+// Get returns the current state of a job
+func (m *Manager) Get(id string) (Job, error) {
+	if !m.Enabled() {
+		return Job{}, ErrDisabled
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.jobs[id]
+	if !exists {
+		return Job{}, ErrNotFound
+	}
+	return j.Job, nil
+}
+
+// Cancel stops a queued or running job. Finished jobs are returned unchanged.
+func (m *Manager) Cancel(id string) (Job, error) {
+	if !m.Enabled() {
+		return Job{}, ErrDisabled
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.jobs[id]
+	if !exists {
+		return Job{}, ErrNotFound
+	}
+	if j.Finished() {
+		return j.Job, nil
+	}
+
+	j.Status = StatusCanceled
+	m.finish(j)
+	if j.cancel != nil {
+		j.cancel()
+	}
+	if err := m.save(j.Job); err != nil {
+		m.logger.Warn().LogErrorf("problem saving canceled job %s: %v", id, err)
+	}
+	return j.Job, nil
+}
+
+// Delete removes a finished job and its files. A canceled job can't be removed until its worker has stopped.
+func (m *Manager) Delete(id string) error {
+	if !m.Enabled() {
+		return ErrDisabled
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.jobs[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if !j.Finished() || j.cancel != nil {
+		return ErrNotFinished
+	}
+
+	delete(m.jobs, id)
+	if err := os.RemoveAll(m.jobDir(id)); err != nil {
+		return fmt.Errorf("removing job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Results opens a completed job's results, which have one Result per line
+func (m *Manager) Results(id string) (*os.File, error) {
+	j, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != StatusCompleted {
+		return nil, ErrNotFinished
+	}
+	return os.Open(filepath.Join(m.jobDir(id), resultsFilename))
+}
+
+func (m *Manager) maxQueued() int {
+	if m.conf.MaxQueued <= 0 {
+		return defaultMaxQueued
+	}
+	return m.conf.MaxQueued
+}
+
+func (m *Manager) maxUploadSize() int64 {
+	if m.conf.MaxUploadSize <= 0 {
+		return defaultMaxUploadSize
+	}
+	return m.conf.MaxUploadSize
+}
+
+func (m *Manager) retention() time.Duration {
+	if m.conf.Retention <= 0 {
+		return defaultRetention
+	}
+	return m.conf.Retention
+}
+
+func (m *Manager) jobDir(id string) string {
+	return filepath.Join(m.conf.Directory, id)
+}
+
+func newID() (string, error) {
+	bs := make([]byte, 16)
+	if _, err := rand.Read(bs); err != nil {
+		return "", fmt.Errorf("creating job ID: %w", err)
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+// notify wakes an idle worker
+func (m *Manager) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) work(ctx context.Context) {
+	for {
+		id, jobCtx := m.next(ctx)
+		if id == "" {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.wake:
+				continue
+			}
+		}
+		m.run(ctx, jobCtx, id)
+	}
+}
+
+// next marks the oldest queued job as running
+func (m *Manager) next(ctx context.Context) (string, context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return "", nil
+	}
+
+	var queued []*job
+	for _, j := range m.jobs {
+		if j.Status == StatusQueued {
+			queued = append(queued, j)
+		}
+	}
+	if len(queued) == 0 {
+		return "", nil
+	}
+	sort.Slice(queued, func(i, k int) bool {
+		if queued[i].CreatedAt.Equal(queued[k].CreatedAt) {
+			return queued[i].ID < queued[k].ID
+		}
+		return queued[i].CreatedAt.Before(queued[k].CreatedAt)
+	})
+	if len(queued) > 1 {
+		m.notify() // another worker can start the next job
+	}
+
+	j := queued[0]
+	now := time.Now().In(time.UTC)
+	j.Status = StatusRunning
+	j.StartedAt = &now
+
+	jobCtx, cancelFunc := context.WithCancel(ctx)
+	j.cancel = cancelFunc
+
+	if err := m.save(j.Job); err != nil {
+		m.logger.Warn().LogErrorf("problem saving job %s: %v", j.ID, err)
+	}
+	return j.ID, jobCtx
+}
+
+func (m *Manager) run(ctx, jobCtx context.Context, id string) {
+	logger := m.logger.With(log.Fields{
+		"job_id": log.String(id),
+	})
+	logger.Info().Log("starting job")
+
+	err := m.search(jobCtx, id)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.jobs[id]
+	if !exists {
+		return // removed while running
+	}
+	j.cancel()
+	j.cancel = nil
+
+	switch {
+	case j.Status == StatusCanceled:
+		logger.Info().Log("job was canceled")
+		os.Remove(filepath.Join(m.jobDir(id), resultsFilename))
+		return // already saved
+
+	case ctx.Err() != nil:
+		// Shutting down, so run the job again on startup
+		logger.Info().Log("job was interrupted and will be queued again")
+		os.Remove(filepath.Join(m.jobDir(id), resultsFilename))
+		j.Status = StatusQueued
+		j.StartedAt = nil
+		j.Rows, j.Processed, j.Matched = 0, 0, 0
+
+	case err != nil:
+		logger.Error().LogErrorf("job failed: %v", err)
+		j.Status = StatusFailed
+		j.Error = err.Error()
+		m.finish(j)
+
+	default:
+		logger.Info().Logf("job completed with %d of %d rows matched", j.Matched, j.Rows)
+		j.Status = StatusCompleted
+		m.finish(j)
+	}
+
+	if err := m.save(j.Job); err != nil {
+		logger.Warn().LogErrorf("problem saving job: %v", err)
+	}
+}
+
+func (m *Manager) finish(j *job) {
+	now := time.Now().In(time.UTC)
+	expires := now.Add(m.retention())
+	j.FinishedAt = &now
+	j.ExpiresAt = &expires
+}
+
+// removeExpired deletes finished jobs which are past their retention
+func (m *Manager) removeExpired(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, j := range m.jobs {
+		if !j.Finished() || j.cancel != nil || j.ExpiresAt == nil || j.ExpiresAt.After(now) {
+			continue
+		}
+		if err := os.RemoveAll(m.jobDir(id)); err != nil {
+			m.logger.Warn().LogErrorf("problem removing expired job %s: %v", id, err)
+			continue
+		}
+		delete(m.jobs, id)
+	}
+}
+
+// load reads the jobs saved in Directory and queues any which didn't finish
+func (m *Manager) load() error {
+	dirs, err := os.ReadDir(m.conf.Directory)
+	if err != nil {
+		return err
+	}
+
+	var requeued int
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		bs, err := os.ReadFile(filepath.Join(m.conf.Directory, dir.Name(), jobFilename))
+		if err != nil {
+			m.logger.Warn().LogErrorf("skipping job directory %s: %v", dir.Name(), err)
+			continue
+		}
+		var j Job
+		if err := json.Unmarshal(bs, &j); err != nil {
+			m.logger.Warn().LogErrorf("skipping job directory %s: %v", dir.Name(), err)
+			continue
+		}
+
+		if !j.Finished() {
+			os.Remove(filepath.Join(m.conf.Directory, dir.Name(), resultsFilename))
+			j.Status = StatusQueued
+			j.StartedAt = nil
+			j.Rows, j.Processed, j.Matched = 0, 0, 0
+			if err := m.save(j); err != nil {
+				return err
+			}
+			requeued++
+		}
+		m.jobs[j.ID] = &job{Job: j}
+	}
+
+	if len(m.jobs) > 0 {
+		m.logger.Info().Logf("loaded %d jobs, %d of which were queued again", len(m.jobs), requeued)
+	}
+	return nil
+}
+
+// save writes the job's state, replacing the previous file only once it's complete
+func (m *Manager) save(j Job) error {
+	bs, err := json.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("encoding job %s: %w", j.ID, err)
+	}
+
+	where := filepath.Join(m.jobDir(j.ID), jobFilename)
+	if err := os.WriteFile(where+".tmp", bs, 0644); err != nil {
+		return fmt.Errorf("saving job %s: %w", j.ID, err)
+	}
+	if err := os.Rename(where+".tmp", where); err != nil {
+		return fmt.Errorf("saving job %s: %w", j.ID, err)
+	}
+	return nil
+}
+
+// saveUpload writes the file and checks its header has the mapped columns and a name
+func saveUpload(where string, file io.Reader, columns custom_list.Columns) error {
+	fd, err := os.Create(where)
+	if err != nil {
+		return fmt.Errorf("saving upload: %w", err)
+	}
+	if _, err := io.Copy(fd, file); err != nil {
+		fd.Close()
+		return fmt.Errorf("saving upload: %w", err)
+	}
+	if err := fd.Close(); err != nil {
+		return fmt.Errorf("saving upload: %w", err)
+	}
+
+	fd, err = os.Open(where)
+	if err != nil {
+		return fmt.Errorf("reading upload: %w", err)
+	}
+	defer fd.Close()
+
+	header, err := csv.NewReader(fd).Read()
+	if err != nil {
+		return fmt.Errorf("%w: reading CSV header: %v", ErrInvalidUpload, err)
+	}
+	found := make(map[string]bool, len(header))
+	for _, column := range header {
+		found[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = true
+	}
+
+	nameColumn := "name"
+	for field, column := range columns {
+		if !knownField(field) {
+			return fmt.Errorf("%w: unknown field %q in columns", ErrInvalidUpload, field)
+		}
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !found[column] {
+			return fmt.Errorf("%w: column %q for %s is not in the file", ErrInvalidUpload, column, field)
+		}
+		if strings.EqualFold(field, "name") {
+			nameColumn = column
+		}
+	}
+	if !found[nameColumn] {
+		return fmt.Errorf("%w: file has no %q column, map the name field to a column", ErrInvalidUpload, nameColumn)
+	}
+	return nil
+}
+
+func knownField(field string) bool {
+	for _, f := range custom_list.Fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	searcher "github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/pkg/custom_list"
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func testService(t *testing.T) searcher.Service {
+	t.Helper()
+
+	svc := searcher.NewService(log.NewTestLogger(), nil)
+	svc.UpdateEntities([]search.Entity[search.Value]{
+		{
+			Name:     "John Doe",
+			Type:     search.EntityPerson,
+			Source:   search.SourceUSOFAC,
+			SourceID: "1",
+			Person:   &search.Person{Name: "John Doe"},
+		},
+		{
+			Name:     "Acme Corp",
+			Type:     search.EntityBusiness,
+			Source:   search.SourceUSOFAC,
+			SourceID: "2",
+			Business: &search.Business{Name: "Acme Corp"},
+		},
+	})
+	return svc
+}
+
+const customers = `Customer ID,Full Name,Kind
+c1,John Doe,person
+c2,Acme Corp,business
+c3,,person
+`
+
+var customerColumns = custom_list.Columns{
+	"sourceID": "Customer ID",
+	"name":     "Full Name",
+	"type":     "Kind",
+}
+
+func waitFor(t *testing.T, m *Manager, id string, status Status) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		require.NoError(t, err)
+		return job.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: dir}, testService(t))
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	m.Start(ctx)
+
+	job, err := m.Create("customers.csv", strings.NewReader(customers), Options{Columns: customerColumns, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, job.Status)
+
+	job = waitFor(t, m, job.ID, StatusCompleted)
+	require.Equal(t, 3, job.Rows)
+	require.Equal(t, 3, job.Processed)
+	require.Equal(t, 2, job.Matched)
+	require.NotNil(t, job.ExpiresAt)
+
+	results, err := m.Results(job.ID)
+	require.NoError(t, err)
+	defer results.Close()
+
+	var sb strings.Builder
+	require.NoError(t, writeCSV(&sb, results))
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	require.Len(t, lines, 4)
+	require.True(t, strings.HasPrefix(lines[1], "1,c1,John Doe,John Doe,person,us_ofac,1,"), lines[1])
+	require.True(t, strings.HasPrefix(lines[2], "2,c2,Acme Corp,Acme Corp,business,us_ofac,2,"), lines[2])
+	require.Equal(t, "3,c3,,,,,,,missing name", lines[3])
+
+	// finished jobs are removed after their retention
+	m.removeExpired(job.ExpiresAt.Add(time.Second))
+	_, err = m.Get(job.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoDirExists(t, filepath.Join(dir, job.ID))
+}
+
+func TestManager_Uploads(t *testing.T) {
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: t.TempDir(), MaxQueued: 1}, testService(t))
+	require.NoError(t, err)
+
+	_, err = m.Create("a.csv", strings.NewReader("Full Name\nJohn Doe\n"), Options{})
+	require.ErrorIs(t, err, ErrInvalidUpload)
+	require.ErrorContains(t, err, `file has no "name" column`)
+
+	_, err = m.Create("a.csv", strings.NewReader(customers), Options{Columns: custom_list.Columns{"name": "Name"}})
+	require.ErrorContains(t, err, `column "name" for name is not in the file`)
+
+	_, err = m.Create("a.csv", strings.NewReader(customers), Options{Columns: custom_list.Columns{"nickname": "Full Name"}})
+	require.ErrorContains(t, err, `unknown field "nickname"`)
+
+	// workers haven't started, so jobs stay queued
+	job, err := m.Create("a.csv", strings.NewReader(customers), Options{Columns: customerColumns})
+	require.NoError(t, err)
+
+	_, err = m.Create("b.csv", strings.NewReader(customers), Options{Columns: customerColumns})
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	_, err = m.Results(job.ID)
+	require.ErrorIs(t, err, ErrNotFinished)
+
+	job, err = m.Cancel(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, job.Status)
+
+	require.NoError(t, m.Delete(job.ID))
+	_, err = m.Get(job.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var disabled *Manager
+	_, err = disabled.Get("123")
+	require.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestManager_CreateConcurrent(t *testing.T) {
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: t.TempDir(), MaxQueued: 3}, testService(t))
+	require.NoError(t, err)
+
+	// every upload checks the queue while the others are being saved
+	var wg sync.WaitGroup
+	var created, full atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := m.Create("a.csv", strings.NewReader(customers), Options{Columns: customerColumns})
+			switch {
+			case err == nil:
+				created.Add(1)
+			case errors.Is(err, ErrQueueFull):
+				full.Add(1)
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(3), created.Load())
+	require.Equal(t, int32(7), full.Load())
+}
+
+// blockingService waits for release before searching, even when the job is canceled
+type blockingService struct {
+	searcher.Service
+	release chan struct{}
+}
+
+func (s *blockingService) Search(ctx context.Context, query search.Entity[search.Value], opts searcher.SearchOpts) ([]search.SearchedEntity[search.Value], error) {
+	<-s.release
+	return s.Service.Search(ctx, query, opts)
+}
+
+func TestManager_DeleteRunning(t *testing.T) {
+	svc := &blockingService{Service: testService(t), release: make(chan struct{})}
+	m, err := NewManager(log.NewTestLogger(), Config{Directory: t.TempDir()}, svc)
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	m.Start(ctx)
+
+	job, err := m.Create("customers.csv", strings.NewReader(customers), Options{Columns: customerColumns})
+	require.NoError(t, err)
+	waitFor(t, m, job.ID, StatusRunning)
+
+	job, err = m.Cancel(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, job.Status)
+
+	// the worker is still searching, so the job can't be removed yet
+	require.ErrorIs(t, m.Delete(job.ID), ErrNotFinished)
+	require.ErrorIs(t, m.Delete(job.ID), ErrNotFinished)
+
+	close(svc.release)
+	require.Eventually(t, func() bool {
+		return m.Delete(job.ID) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = m.Get(job.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_Restart(t *testing.T) {
+	dir := t.TempDir()
+	conf := Config{Directory: dir}
+
+	m, err := NewManager(log.NewTestLogger(), conf, testService(t))
+	require.NoError(t, err)
+
+	job, err := m.Create("customers.csv", strings.NewReader(customers), Options{Columns: customerColumns})
+	require.NoError(t, err)
+
+	// a job which was running when the process stopped is queued again
+	m.mu.Lock()
+	m.jobs[job.ID].Status = StatusRunning
+	require.NoError(t, m.save(m.jobs[job.ID].Job))
+	m.mu.Unlock()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, job.ID, resultsFilename), []byte("partial"), 0644))
+
+	m, err = NewManager(log.NewTestLogger(), conf, testService(t))
+	require.NoError(t, err)
+
+	found, err := m.Get(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusQueued, found.Status)
+	require.NoFileExists(t, filepath.Join(dir, job.ID, resultsFilename))
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	m.Start(ctx)
+
+	found = waitFor(t, m, job.ID, StatusCompleted)
+	require.Equal(t, 2, found.Matched)
+}
@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	searcher "github.com/moov-io/watchman/internal/search"
+	"github.com/moov-io/watchman/pkg/custom_list"
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// Result is the matches found for one row of a job's file
+type Result struct {
+	Row int `json:"row"`
+
+	// ReferenceID is the row's sourceID column, or "<filename>:<row>" without one
+	ReferenceID string `json:"referenceID"`
+	Name        string `json:"name"`
+
+	Entities []search.SearchedEntity[search.Value] `json:"entities"`
+	Error    string                                `json:"error,omitempty"`
+}
+
+// search reads the job's file and writes a Result for each row
+func (m *Manager) search(ctx context.Context, id string) error {
+	m.mu.Lock()
+	found, exists := m.jobs[id]
+	if !exists {
+		m.mu.Unlock()
+		return ErrNotFound
+	}
+	j := found.Job
+	m.mu.Unlock()
+
+	// The first pass counts the rows, and fails the job on a malformed row before anything is searched
+	dir := m.jobDir(id)
+	var rows int
+	err := m.readUpload(j, func(custom_list.Record) error {
+		rows++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !m.update(id, func(j *job) { j.Rows = rows }) {
+		return ErrNotFound
+	}
+
+	out, err := os.Create(filepath.Join(dir, resultsFilename))
+	if err != nil {
+		return fmt.Errorf("creating results: %w", err)
+	}
+	defer out.Close()
+
+	buf := bufio.NewWriter(out)
+	enc := json.NewEncoder(buf)
+	err = m.readUpload(j, func(record custom_list.Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result := m.searchRow(ctx, j, record)
+		if err := enc.Encode(result); err != nil {
+			return fmt.Errorf("writing results: %w", err)
+		}
+
+		updated := m.update(id, func(j *job) {
+			j.Processed++
+			if len(result.Entities) > 0 {
+				j.Matched++
+			}
+		})
+		if !updated {
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("writing results: %w", err)
+	}
+	return nil
+}
+
+// readUpload calls fn with each row of the job's file as it's read
+func (m *Manager) readUpload(j Job, fn func(record custom_list.Record) error) error {
+	fd, err := os.Open(filepath.Join(m.jobDir(j.ID), uploadFilename))
+	if err != nil {
+		return fmt.Errorf("opening upload: %w", err)
+	}
+	defer fd.Close()
+
+	reader := custom_list.NewCSVReader(j.Filename, bufio.NewReader(fd))
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading upload: %w", err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// update changes a job's progress, returning false when it has been removed
+func (m *Manager) update(id string, fn func(j *job)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, exists := m.jobs[id]
+	if exists {
+		fn(j)
+	}
+	return exists
+}
+
+func (m *Manager) searchRow(ctx context.Context, j Job, record custom_list.Record) Result {
+	query := custom_list.ToEntity(search.SourceAPIRequest, j.Options.Columns, record)
+
+	result := Result{
+		Row:         record.Row,
+		ReferenceID: query.SourceID,
+		Name:        query.Name,
+		Entities:    []search.SearchedEntity[search.Value]{},
+	}
+	if query.Name == "" {
+		result.Error = "missing name"
+		return result
+	}
+
+	query.SourceID = ""
+	query.SourceData = nil
+
+	entities, err := m.service.Search(ctx, query, searcher.SearchOpts{
+		Limit:     j.Options.Limit,
+		MinMatch:  j.Options.MinMatch,
+		RequestID: j.ID,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(entities) > 0 {
+		result.Entities = entities
+	}
+	return result
+}
+
+var (
+	csvHeader = []string{
+		"row", "referenceID", "name",
+		"matchName", "matchEntityType", "matchSourceList", "matchSourceID", "match",
+		"error",
+	}
+)
+
+// writeCSV converts NDJSON results into CSV with a line for each match,
+// or a single line for rows without any matches.
+func writeCSV(w io.Writer, results io.Reader) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(results)
+	for {
+		var result Result
+		if err := dec.Decode(&result); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("reading results: %w", err)
+		}
+
+		row := []string{strconv.Itoa(result.Row), result.ReferenceID, result.Name}
+		if len(result.Entities) == 0 {
+			if err := out.Write(append(row, "", "", "", "", "", result.Error)); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, entity := range result.Entities {
+			line := append(row[:3:3],
+				entity.Name, string(entity.Type), string(entity.Source), entity.SourceID,
+				strconv.FormatFloat(entity.Match, 'f', 4, 64),
+				result.Error,
+			)
+			if err := out.Write(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,260 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+	"unicode"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// Config holds the options for search endpoints
+type Config struct {
+	Batch BatchConfig
+}
+
+// BatchConfig limits batch screening on /v2/search/batch
+type BatchConfig struct {
+	// MaxSize is how many queries a batch can contain. Defaults to 10,000.
+	MaxSize int
+
+	// Concurrency is how many queries from a batch are searched at once. Defaults to the number of CPUs.
+	Concurrency int
+}
+
+const (
+	defaultBatchMaxSize = 10_000
+
+	// batchTimeout is how long reading each query or writing each result can take. It replaces
+	// the server's timeouts, which are meant for a single search rather than a long stream.
+	batchTimeout = 30 * time.Second
+)
+
+func (c BatchConfig) maxSize() int {
+	if c.MaxSize <= 0 {
+		return defaultBatchMaxSize
+	}
+	return c.MaxSize
+}
+
+func (c BatchConfig) concurrency() int {
+	if c.Concurrency <= 0 {
+		return runtime.NumCPU()
+	}
+	return c.Concurrency
+}
+
+// batchQuery is one search within a batch, identified by the client's ReferenceID
+type batchQuery struct {
+	ReferenceID string `json:"referenceID"`
+
+	searchRequestBody
+}
+
+// batchResult is written as one line of NDJSON for each query, in the order they were sent
+type batchResult struct {
+	Index       int                                   `json:"index"`
+	ReferenceID string                                `json:"referenceID,omitempty"`
+	Entities    []search.SearchedEntity[search.Value] `json:"entities"`
+	Error       string                                `json:"error,omitempty"`
+}
+
+// searchBatch reads a JSON array or NDJSON stream of queries and writes each result as NDJSON.
+// Queries are searched concurrently while results are written in request order. Only a limited
+// number of queries are read ahead of the results, so slow clients slow down reading.
+func (c *controller) searchBatch(w http.ResponseWriter, r *http.Request) {
+	opts, err := readSearchOpts(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	dec, err := newBatchDecoder(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem reading batch: %w", err))
+		return
+	}
+
+	// Results are written while queries are still being read
+	rc := http.NewResponseController(w)
+	rc.EnableFullDuplex() //nolint:errcheck // HTTP/2 doesn't need it
+
+	ctx, cancelFunc := context.WithCancel(r.Context())
+	pending := make(chan chan batchResult, 2*c.batch.concurrency())
+	go c.readBatch(ctx, rc, dec, opts, pending)
+
+	// The request body can't be read after we return, so wait for readBatch when stopping early
+	defer func() {
+		cancelFunc()
+		for range pending {
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for slot := range pending {
+		var result batchResult
+		select {
+		case result = <-slot:
+		case <-ctx.Done():
+			return
+		}
+
+		rc.SetWriteDeadline(time.Now().Add(batchTimeout)) //nolint:errcheck
+		if err := enc.Encode(result); err != nil {
+			c.logger.Warn().LogErrorf("problem writing batch result: %v", err)
+			return
+		}
+		rc.Flush() //nolint:errcheck
+	}
+}
+
+// readBatch sends a slot for each query's result on pending, in request order, and closes it
+// after the last query. Reading stops at the first query which can't be decoded.
+func (c *controller) readBatch(ctx context.Context, rc *http.ResponseController, dec *batchDecoder, opts SearchOpts, pending chan chan batchResult) {
+	defer close(pending)
+
+	workers := make(chan struct{}, c.batch.concurrency())
+	for index := 0; ; index++ {
+		rc.SetReadDeadline(time.Now().Add(batchTimeout)) //nolint:errcheck
+
+		query, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		slot := make(chan batchResult, 1)
+		select {
+		case pending <- slot:
+		case <-ctx.Done():
+			return
+		}
+
+		if err != nil {
+			slot <- batchResult{Index: index, Error: fmt.Sprintf("problem reading query: %v", err)}
+			return
+		}
+		if index >= c.batch.maxSize() {
+			slot <- batchResult{Index: index, ReferenceID: query.ReferenceID, Error: fmt.Sprintf("batches are limited to %d queries", c.batch.maxSize())}
+			return
+		}
+
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		go func(index int, query batchQuery) {
+			defer func() { <-workers }()
+			slot <- c.searchBatchQuery(ctx, index, query, opts)
+		}(index, query)
+	}
+}
+
+func (c *controller) searchBatchQuery(ctx context.Context, index int, query batchQuery, opts SearchOpts) batchResult {
+	result := batchResult{
+		Index:       index,
+		ReferenceID: query.ReferenceID,
+	}
+
+	opts, err := query.applyOptions(opts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if opts.RequestID == "" {
+		opts.RequestID = query.ReferenceID
+	}
+
+	entities, err := c.service.Search(ctx, prepareSearchEntity(query.Entity), opts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Entities = entities
+	if result.Entities == nil {
+		result.Entities = []search.SearchedEntity[search.Value]{}
+	}
+	return result
+}
+
+// batchDecoder reads queries from either a JSON array or NDJSON. Each query is limited
+// to maxSearchBodySize, the same as a single search.
+type batchDecoder struct {
+	dec   *json.Decoder
+	input *queryLimitReader
+	array bool
+}
+
+var errQueryTooLarge = fmt.Errorf("query is larger than %d bytes", maxSearchBodySize)
+
+// queryLimitReader fails reads past limit, an offset in the underlying stream
+type queryLimitReader struct {
+	r     io.Reader
+	read  int64
+	limit int64
+}
+
+func (l *queryLimitReader) Read(p []byte) (int, error) {
+	if l.read >= l.limit {
+		return 0, errQueryTooLarge
+	}
+	if remaining := l.limit - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
+
+func newBatchDecoder(r io.Reader) (*batchDecoder, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			br.UnreadByte() //nolint:errcheck
+			break
+		}
+	}
+
+	out := &batchDecoder{
+		input: &queryLimitReader{r: br, limit: maxSearchBodySize},
+	}
+	out.dec = json.NewDecoder(out.input)
+	if first, err := br.Peek(1); err == nil && first[0] == '[' {
+		out.array = true
+		if _, err := out.dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// next returns the next query, or io.EOF after the last one
+func (d *batchDecoder) next() (batchQuery, error) {
+	// Anything already read past the previous query belongs to this one
+	d.input.limit = d.dec.InputOffset() + maxSearchBodySize
+
+	var query batchQuery
+	if d.array && !d.dec.More() {
+		return query, io.EOF
+	}
+	err := d.dec.Decode(&query)
+	return query, err
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func readBatchResults(t *testing.T, w *httptest.ResponseRecorder) []batchResult {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var out []batchResult
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var result batchResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		out = append(out, result)
+	}
+	require.NoError(t, scanner.Err())
+	return out
+}
+
+func TestController_SearchBatch(t *testing.T) {
+	router := testRouter(t)
+
+	bodies := map[string]string{
+		"array": `[
+  {"referenceID": "a", "name": "John Doe", "entityType": "person"},
+  {"referenceID": "b", "name": "Acme Corp", "entityType": "business"},
+  {"referenceID": "c", "name": "Jane Doe", "entityType": "person", "limit": 2}
+]`,
+		"ndjson": `{"referenceID": "a", "name": "John Doe", "entityType": "person"}
+{"referenceID": "b", "name": "Acme Corp", "entityType": "business"}
+{"referenceID": "c", "name": "Jane Doe", "entityType": "person", "limit": 2}
+`,
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v2/search/batch?limit=1", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			results := readBatchResults(t, w)
+			require.Len(t, results, 3)
+			for idx, ref := range []string{"a", "b", "c"} {
+				require.Equal(t, idx, results[idx].Index)
+				require.Equal(t, ref, results[idx].ReferenceID)
+				require.Empty(t, results[idx].Error)
+			}
+			require.Len(t, results[0].Entities, 1)
+			require.Equal(t, "1", results[0].Entities[0].SourceID)
+			require.Equal(t, "3", results[1].Entities[0].SourceID)
+			require.Len(t, results[2].Entities, 2) // the query's limit replaces the query string's
+		})
+	}
+}
+
+func TestController_SearchBatchErrors(t *testing.T) {
+	logger := log.NewTestLogger()
+	svc := NewService(logger, nil)
+	router := NewController(logger, svc, Config{Batch: BatchConfig{MaxSize: 2, Concurrency: 1}}).AppendRoutes(mux.NewRouter())
+
+	// queries beyond MaxSize aren't searched
+	body := `{"referenceID": "a", "name": "John Doe"}
+{"referenceID": "b", "name": "Jane Doe"}
+{"referenceID": "c", "name": "Acme Corp"}
+{"referenceID": "d", "name": "Acme Corp"}
+`
+	req := httptest.NewRequest("POST", "/v2/search/batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	results := readBatchResults(t, w)
+	require.Len(t, results, 3)
+	require.NotNil(t, results[0].Entities)
+	require.Equal(t, "c", results[2].ReferenceID)
+	require.Equal(t, "batches are limited to 2 queries", results[2].Error)
+
+	// invalid options fail only their query, while invalid JSON stops the batch
+	body = `{"referenceID": "a", "name": "John Doe", "asOf": "yesterday"}
+{"referenceID": "b", "name": "Jane Doe"}
+{"referenceID": "c", "name":
+`
+	req = httptest.NewRequest("POST", "/v2/search/batch", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	results = readBatchResults(t, w)
+	require.Len(t, results, 3)
+	require.NotEmpty(t, results[0].Error)
+	require.Empty(t, results[1].Error)
+	require.Contains(t, results[2].Error, "problem reading query")
+
+	// each query is limited to the size of a single search
+	for _, format := range []string{"%s\n%s\n", "[%s, %s]"} {
+		large := `{"referenceID": "b", "name": "` + strings.Repeat("a", maxSearchBodySize) + `"}`
+		body = fmt.Sprintf(format, `{"referenceID": "a", "name": "John Doe"}`, large)
+		req = httptest.NewRequest("POST", "/v2/search/batch", strings.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		results = readBatchResults(t, w)
+		require.Len(t, results, 2)
+		require.Empty(t, results[0].Error)
+		require.Equal(t, 1, results[1].Index)
+		require.Contains(t, results[1].Error, "query is larger than 1048576 bytes")
+	}
+
+	// while the whole batch can be larger
+	medium := `{"name": "` + strings.Repeat("a", maxSearchBodySize/2) + `"}`
+	req = httptest.NewRequest("POST", "/v2/search/batch", strings.NewReader(strings.Repeat(medium+"\n", 2)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	results = readBatchResults(t, w)
+	require.Len(t, results, 2)
+	require.Empty(t, results[1].Error)
+
+	// empty batches have no results
+	req = httptest.NewRequest("POST", "/v2/search/batch", strings.NewReader(" [ ] "))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Empty(t, readBatchResults(t, w))
+}
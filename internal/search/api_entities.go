@@ -0,0 +1,123 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/moov-io/watchman/internal/snapshot"
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	maxLookupKeys = 1000
+)
+
+func (c *controller) getEntity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	key := EntityKey{
+		Source:   search.SourceList(vars["source"]),
+		SourceID: vars["sourceID"],
+	}.normalize()
+
+	asOf, err := readAsOf(r.URL.Query().Get("asOf"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	found, err := c.service.Lookup([]EntityKey{key}, asOf)
+	if err != nil {
+		c.writeLookupError(w, err)
+		return
+	}
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("entity %s/%s not found", key.Source, key.SourceID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(found) > 1 {
+		// The list published several records with this ID, so return each of them
+		w.WriteHeader(http.StatusMultipleChoices)
+		json.NewEncoder(w).Encode(multipleEntitiesResponse{Entities: found})
+		return
+	}
+	json.NewEncoder(w).Encode(found[0])
+}
+
+type multipleEntitiesResponse struct {
+	Entities []search.Entity[search.Value] `json:"entities"`
+}
+
+type lookupRequest struct {
+	Entities []EntityKey `json:"entities"`
+	AsOf     string      `json:"asOf,omitempty"`
+}
+
+type lookupResponse struct {
+	Entities []search.Entity[search.Value] `json:"entities"`
+	NotFound []EntityKey                   `json:"notFound"`
+}
+
+func (c *controller) lookupEntities(w http.ResponseWriter, r *http.Request) {
+	var req lookupRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSearchBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem reading lookup request: %w", err))
+		return
+	}
+	if len(req.Entities) > maxLookupKeys {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("lookups are limited to %d entities", maxLookupKeys))
+		return
+	}
+	for i := range req.Entities {
+		req.Entities[i] = req.Entities[i].normalize()
+	}
+
+	asOf, err := readAsOf(req.AsOf)
+	if err == nil && asOf == nil {
+		asOf, err = readAsOf(r.URL.Query().Get("asOf"))
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	found, err := c.service.Lookup(req.Entities, asOf)
+	if err != nil {
+		c.writeLookupError(w, err)
+		return
+	}
+
+	resp := lookupResponse{
+		Entities: found,
+		NotFound: []EntityKey{},
+	}
+	if resp.Entities == nil {
+		resp.Entities = []search.Entity[search.Value]{}
+	}
+	seen := make(map[EntityKey]bool, len(found))
+	for _, entity := range found {
+		seen[entityKey(entity)] = true
+	}
+	for _, key := range req.Entities {
+		if !seen[key] {
+			resp.NotFound = append(resp.NotFound, key)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (c *controller) writeLookupError(w http.ResponseWriter, err error) {
+	if errors.Is(err, snapshot.ErrNoHistory) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	c.logger.Error().LogErrorf("problem looking up entities: %v", err)
+	writeError(w, http.StatusInternalServerError, errors.New("problem looking up entities"))
+}
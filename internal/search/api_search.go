@@ -1,13 +1,16 @@
 package search
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/moov-io/watchman/internal/snapshot"
 	"github.com/moov-io/watchman/pkg/address"
 	"github.com/moov-io/watchman/pkg/search"
 
@@ -20,16 +23,18 @@ type Controller interface {
 	AppendRoutes(router *mux.Router) *mux.Router
 }
 
-func NewController(logger log.Logger, service Service) Controller {
+func NewController(logger log.Logger, service Service, conf Config) Controller {
 	return &controller{
 		logger:  logger,
 		service: service,
+		batch:   conf.Batch,
 	}
 }
 
 type controller struct {
 	logger  log.Logger
 	service Service
+	batch   BatchConfig
 }
 
 func (c *controller) AppendRoutes(router *mux.Router) *mux.Router {
@@ -39,6 +44,36 @@ func (c *controller) AppendRoutes(router *mux.Router) *mux.Router {
 		Path("/v2/search").
 		HandlerFunc(c.search)
 
+	router.
+		Name("SearchJSON.v2").
+		Methods("POST").
+		Path("/v2/search").
+		HandlerFunc(c.searchJSON)
+
+	router.
+		Name("SearchBatch.v2").
+		Methods("POST").
+		Path("/v2/search/batch").
+		HandlerFunc(c.searchBatch)
+
+	router.
+		Name("Clusters.v2").
+		Methods("GET").
+		Path("/v2/clusters/{clusterID}").
+		HandlerFunc(c.getCluster)
+
+	router.
+		Name("GetEntity.v2").
+		Methods("GET").
+		Path("/v2/entities/{source}/{sourceID:.+}"). // IDs from custom lists can contain slashes
+		HandlerFunc(c.getEntity)
+
+	router.
+		Name("LookupEntities.v2").
+		Methods("POST").
+		Path("/v2/entities/lookup").
+		HandlerFunc(c.lookupEntities)
+
 	return router
 }
 
@@ -58,25 +93,147 @@ func (c *controller) search(w http.ResponseWriter, r *http.Request) {
 		err = fmt.Errorf("problem reading v2 search request: %w", err)
 		c.logger.Error().LogError(err)
 
-		w.WriteHeader(http.StatusBadRequest)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(errorResponse{
-			Error: err.Error(),
-		})
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if debug {
+		c.logger.Debug().Logf("request: %#v", req)
+	}
+
+	opts, err := readSearchOpts(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	c.performSearch(w, r, req, opts, debug)
+}
+
+// searchRequestBody is the body of POST /v2/search, which is a search.Entity along with
+// optional search options. Options in the body replace those from the query string.
+type searchRequestBody struct {
+	search.Entity[search.Value]
+
+	Limit          *int     `json:"limit,omitempty"`
+	MinMatch       *float64 `json:"minMatch,omitempty"`
+	RequestID      string   `json:"requestID,omitempty"`
+	AsOf           string   `json:"asOf,omitempty"`
+	Collapse       *bool    `json:"collapse,omitempty"`
+	Explain        *bool    `json:"explain,omitempty"`
+	DebugSourceIDs []string `json:"debugSourceIDs,omitempty"`
+}
+
+const (
+	maxSearchBodySize = 1 << 20 // 1MB
+)
 
+func (c *controller) searchJSON(w http.ResponseWriter, r *http.Request) {
+	debug := strx.Yes(r.URL.Query().Get("debug"))
+
+	opts, err := readSearchOpts(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
 		return
 	}
+
+	var body searchRequestBody
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSearchBodySize))
+	if err := dec.Decode(&body); err != nil {
+		err = fmt.Errorf("problem reading v2 search request: %w", err)
+		c.logger.Error().LogError(err)
+
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts, err = body.applyOptions(opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req := prepareSearchEntity(body.Entity)
 	if debug {
 		c.logger.Debug().Logf("request: %#v", req)
 	}
 
-	q := r.URL.Query()
-	opts := SearchOpts{
-		Limit:          extractSearchLimit(r),
-		MinMatch:       extractSearchMinMatch(r),
-		RequestID:      q.Get("requestID"),
-		DebugSourceIDs: strings.Split(q.Get("debugSourceIDs"), ","),
+	c.performSearch(w, r, req, opts, debug)
+}
+
+func (body searchRequestBody) applyOptions(opts SearchOpts) (SearchOpts, error) {
+	if body.Limit != nil {
+		opts.Limit = limitResults(*body.Limit)
+	}
+	if body.MinMatch != nil {
+		opts.MinMatch = *body.MinMatch
+	}
+	if body.RequestID != "" {
+		opts.RequestID = body.RequestID
+	}
+	if body.AsOf != "" {
+		asOf, err := readAsOf(body.AsOf)
+		if err != nil {
+			return opts, err
+		}
+		opts.AsOf = asOf
+	}
+	if body.Collapse != nil {
+		opts.CollapseClusters = *body.Collapse
+	}
+	if body.Explain != nil {
+		opts.Explain = *body.Explain
 	}
+	if len(body.DebugSourceIDs) > 0 {
+		opts.DebugSourceIDs = body.DebugSourceIDs
+	}
+	return opts, nil
+}
+
+// prepareSearchEntity fills in what readSearchRequest would for a query from the URL,
+// such as the name of the person or business being searched for.
+func prepareSearchEntity(req search.Entity[search.Value]) search.Entity[search.Value] {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Type = search.EntityType(strings.TrimSpace(strings.ToLower(string(req.Type))))
+	req.Source = search.SourceAPIRequest
+	req.SourceID = ""
+	req.SourceData = nil
+
+	switch req.Type {
+	case search.EntityPerson:
+		if req.Person == nil {
+			req.Person = &search.Person{}
+		}
+		req.Person.Name = cmp.Or(strings.TrimSpace(req.Person.Name), req.Name)
+
+	case search.EntityBusiness:
+		if req.Business == nil {
+			req.Business = &search.Business{}
+		}
+		req.Business.Name = cmp.Or(strings.TrimSpace(req.Business.Name), req.Name)
+
+	case search.EntityOrganization:
+		if req.Organization == nil {
+			req.Organization = &search.Organization{}
+		}
+		req.Organization.Name = cmp.Or(strings.TrimSpace(req.Organization.Name), req.Name)
+
+	case search.EntityAircraft:
+		if req.Aircraft == nil {
+			req.Aircraft = &search.Aircraft{}
+		}
+		req.Aircraft.Name = cmp.Or(strings.TrimSpace(req.Aircraft.Name), req.Name)
+
+	case search.EntityVessel:
+		if req.Vessel == nil {
+			req.Vessel = &search.Vessel{}
+		}
+		req.Vessel.Name = cmp.Or(strings.TrimSpace(req.Vessel.Name), req.Name)
+	}
+
+	return req
+}
+
+func (c *controller) performSearch(w http.ResponseWriter, r *http.Request, req search.Entity[search.Value], opts SearchOpts, debug bool) {
 	if debug {
 		c.logger.Debug().Logf("opts: %#v", opts)
 	}
@@ -85,6 +242,11 @@ func (c *controller) search(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		c.logger.Error().LogErrorf("problem with v2 search: %v", err)
 
+		if errors.Is(err, snapshot.ErrNoHistory) {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -98,6 +260,27 @@ func (c *controller) search(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{
+		Error: err.Error(),
+	})
+}
+
+func (c *controller) getCluster(w http.ResponseWriter, r *http.Request) {
+	clusterID := mux.Vars(r)["clusterID"]
+
+	cluster, found := c.service.Cluster(clusterID)
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("cluster %s not found", clusterID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cluster)
+}
+
 var (
 	softResultsLimit, hardResultsLimit = 10, 100
 )
@@ -110,8 +293,15 @@ func extractSearchLimit(r *http.Request) int {
 			limit = n
 		}
 	}
+	return limitResults(limit)
+}
+
+func limitResults(limit int) int {
+	if limit <= 0 {
+		return softResultsLimit
+	}
 	if limit > hardResultsLimit {
-		limit = hardResultsLimit
+		return hardResultsLimit
 	}
 	return limit
 }
@@ -124,6 +314,37 @@ func extractSearchMinMatch(r *http.Request) float64 {
 	return 0.00
 }
 
+// readSearchOpts reads the search options from the query string
+func readSearchOpts(r *http.Request) (SearchOpts, error) {
+	q := r.URL.Query()
+	opts := SearchOpts{
+		Limit:          extractSearchLimit(r),
+		MinMatch:       extractSearchMinMatch(r),
+		RequestID:      q.Get("requestID"),
+		DebugSourceIDs: strings.Split(q.Get("debugSourceIDs"), ","),
+
+		CollapseClusters: strx.Yes(q.Get("collapse")),
+		Explain:          strx.Yes(q.Get("explain")),
+	}
+
+	var err error
+	opts.AsOf, err = readAsOf(q.Get("asOf"))
+	return opts, err
+}
+
+// readAsOf reads the asOf date (YYYY-MM-DD) to search the entities from that day
+func readAsOf(v string) (*time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil, nil
+	}
+	asOf, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asOf date %q, expected YYYY-MM-DD", v)
+	}
+	return &asOf, nil
+}
+
 func readSearchRequest(r *http.Request) (search.Entity[search.Value], error) {
 	q := r.URL.Query()
 
@@ -167,7 +388,7 @@ func readSearchRequest(r *http.Request) (search.Entity[search.Value], error) {
 			Name:         req.Name,
 			Type:         search.AircraftType(q.Get("aircraftType")),
 			Flag:         q.Get("flag"),
-			Built:        readDate("built"),
+			Built:        readDate(q.Get("built")),
 			ICAOCode:     q.Get("icaoCode"),
 			Model:        q.Get("model"),
 			SerialNumber: q.Get("serialNumber"),
@@ -179,7 +400,7 @@ func readSearchRequest(r *http.Request) (search.Entity[search.Value], error) {
 			IMONumber: q.Get("imoNumber"),
 			Type:      search.VesselType(q.Get("vesselType")),
 			Flag:      q.Get("flag"),
-			Built:     readDate("built"),
+			Built:     readDate(q.Get("built")),
 			Model:     q.Get("model"),
 			MMSI:      q.Get("mmsi"),
 			CallSign:  q.Get("callSign"),
@@ -0,0 +1,275 @@
+package search
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/dpl"
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/gorilla/mux"
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func testRouter(t *testing.T) *mux.Router {
+	t.Helper()
+
+	logger := log.NewTestLogger()
+	svc := NewService(logger, nil)
+	svc.UpdateEntities([]search.Entity[search.Value]{
+		{
+			Name:     "John Doe",
+			Type:     search.EntityPerson,
+			Source:   search.SourceUSOFAC,
+			SourceID: "1",
+			Person: &search.Person{
+				Name: "John Doe",
+				GovernmentIDs: []search.GovernmentID{
+					{Type: search.GovernmentIDPassport, Country: "US", Identifier: "X1234567"},
+				},
+			},
+		},
+		{
+			Name:     "Jane Doe",
+			Type:     search.EntityPerson,
+			Source:   search.SourceUSOFAC,
+			SourceID: "2",
+			Person:   &search.Person{Name: "Jane Doe"},
+		},
+		{
+			Name:     "Acme Corp",
+			Type:     search.EntityBusiness,
+			Source:   search.SourceUSOFAC,
+			SourceID: "3",
+			Business: &search.Business{Name: "Acme Corp"},
+		},
+	})
+
+	return NewController(logger, svc, Config{}).AppendRoutes(mux.NewRouter())
+}
+
+func TestController_SearchJSON(t *testing.T) {
+	router := testRouter(t)
+
+	body := `{"name": "John Doe", "entityType": "Person", "person": {"governmentIDs": [{"type": "passport", "country": "US", "identifier": "X1234567"}]}, "limit": 1}`
+	req := httptest.NewRequest("POST", "/v2/search?limit=5", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp searchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Entities, 1) // the body's limit replaces the query's
+	require.Equal(t, "1", resp.Entities[0].SourceID)
+
+	// options not in the body are read from the query string
+	req = httptest.NewRequest("POST", "/v2/search?limit=2", strings.NewReader(`{"name": "Doe", "entityType": "person"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp = searchResponse{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Entities, 2)
+
+	// invalid bodies and options are rejected
+	for _, body := range []string{`{"name": `, `{"name": "John Doe", "asOf": "yesterday"}`, `{"name": "John Doe", "limit": "one"}`} {
+		req = httptest.NewRequest("POST", "/v2/search", strings.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+		require.Contains(t, w.Body.String(), `"error"`)
+	}
+}
+
+func TestPrepareSearchEntity(t *testing.T) {
+	req := prepareSearchEntity(search.Entity[search.Value]{
+		Name:     " Acme Corp ",
+		Type:     "BUSINESS",
+		Source:   search.SourceUSOFAC,
+		SourceID: "123",
+	})
+	require.Equal(t, search.EntityBusiness, req.Type)
+	require.Equal(t, search.SourceAPIRequest, req.Source)
+	require.Empty(t, req.SourceID)
+	require.Equal(t, "Acme Corp", req.Business.Name)
+
+	req = prepareSearchEntity(search.Entity[search.Value]{
+		Name:   "John Doe",
+		Type:   search.EntityPerson,
+		Person: &search.Person{Name: "Johnny Doe"},
+	})
+	require.Equal(t, "Johnny Doe", req.Person.Name)
+}
+
+func TestReadSearchRequest_Built(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v2/search?name=Ever+Given&type=vessel&built=2018-03-01&tonnage=1&grossRegisteredTonnage=1", nil)
+
+	entity, err := readSearchRequest(req)
+	require.NoError(t, err)
+	require.NotNil(t, entity.Vessel.Built)
+	require.Equal(t, time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC), *entity.Vessel.Built)
+
+	req = httptest.NewRequest("GET", "/v2/search?name=Cessna&type=aircraft&built=1999", nil)
+	entity, err = readSearchRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, 1999, entity.Aircraft.Built.Year())
+}
+
+func TestController_SearchExplain(t *testing.T) {
+	router := testRouter(t)
+
+	req := httptest.NewRequest("GET", "/v2/search?name=John+Doe&type=person&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"explanation"`)
+
+	req = httptest.NewRequest("GET", "/v2/search?name=John+Doe&type=person&limit=2&explain=yes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp searchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Entities, 2)
+	for _, entity := range resp.Entities {
+		require.NotNil(t, entity.Explanation)
+		require.InDelta(t, entity.Match, entity.Explanation.FinalScore, 0.0001)
+		require.NotEmpty(t, entity.Explanation.Pieces)
+	}
+	require.Equal(t, "John Doe", resp.Entities[0].Explanation.BestName.Name)
+	require.Equal(t, search.NameKindPrimary, resp.Entities[0].Explanation.BestName.Kind)
+
+	// explanations can be asked for in JSON bodies
+	req = httptest.NewRequest("POST", "/v2/search", strings.NewReader(`{"name": "Acme Corp", "entityType": "business", "explain": true}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"explanation"`)
+}
+
+func TestController_Entities(t *testing.T) {
+	router := testRouter(t)
+
+	req := httptest.NewRequest("GET", "/v2/entities/US_OFAC/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var entity search.Entity[search.Value]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&entity))
+	require.Equal(t, "John Doe", entity.Name)
+	require.Len(t, entity.Person.GovernmentIDs, 1)
+
+	req = httptest.NewRequest("GET", "/v2/entities/us_ofac/404", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), "entity us_ofac/404 not found")
+
+	body := `{"entities": [{"source": "us_ofac", "sourceID": "3"}, {"source": "eu_csl", "sourceID": "1"}, {"source": "us_ofac", "sourceID": "2"}]}`
+	req = httptest.NewRequest("POST", "/v2/entities/lookup", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp lookupResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Entities, 2)
+	require.Equal(t, "Acme Corp", resp.Entities[0].Name)
+	require.Equal(t, "Jane Doe", resp.Entities[1].Name)
+	require.Equal(t, []EntityKey{{Source: search.SourceEUCSL, SourceID: "1"}}, resp.NotFound)
+
+	// past dates need history
+	req = httptest.NewRequest("POST", "/v2/entities/lookup?asOf=2020-01-01", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest("POST", "/v2/entities/lookup", strings.NewReader(`{"entities": [`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestController_EntitySourceIDs(t *testing.T) {
+	logger := log.NewTestLogger()
+
+	denied := dpl.ToEntity(dpl.DPL{
+		Name:          "ADRIAN MANUEL HERNANDEZ",
+		StreetAddress: "3037 S. 69TH DRIVE",
+		City:          "PHONEIX",
+		EffectiveDate: "10/16/2017",
+		FRCitation:    "82 F.R. 48792 10/20/2017",
+	})
+	custom := func(sourceID, name string) search.Entity[search.Value] {
+		return search.Entity[search.Value]{
+			Name:     name,
+			Type:     search.EntityPerson,
+			Source:   search.SourceList("FormerCustomers"),
+			SourceID: sourceID,
+			Person:   &search.Person{Name: name},
+		}
+	}
+
+	svc := NewService(logger, nil)
+	svc.UpdateEntities([]search.Entity[search.Value]{
+		denied,
+		custom("c1", "John Doe"),
+		custom("a/b#1", "Jane Doe"),
+		custom("dup", "First Record"),
+		custom("dup", "Second Record"),
+	})
+	router := NewController(logger, svc, Config{}).AppendRoutes(mux.NewRouter())
+
+	get := func(source, sourceID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/v2/entities/"+source+"/"+url.PathEscape(sourceID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("us_dpl", denied.SourceID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var entity search.Entity[search.Value]
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&entity))
+	require.Equal(t, "ADRIAN MANUEL HERNANDEZ", entity.Name)
+
+	// slashes and fragments in IDs are escaped
+	w = get("FormerCustomers", "a/b#1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), "Jane Doe")
+
+	// sources are case-insensitive
+	for _, source := range []string{"FormerCustomers", "formercustomers"} {
+		w = get(source, "c1")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Contains(t, w.Body.String(), "John Doe")
+	}
+
+	body := `{"entities": [{"source": "FormerCustomers", "sourceID": "c1"}]}`
+	req := httptest.NewRequest("POST", "/v2/entities/lookup", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp lookupResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Entities, 1)
+	require.Empty(t, resp.NotFound)
+
+	// every record sharing an ID is returned
+	w = get("FormerCustomers", "dup")
+	require.Equal(t, http.StatusMultipleChoices, w.Code)
+	var multiple multipleEntitiesResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&multiple))
+	require.Len(t, multiple.Entities, 2)
+	require.Equal(t, "First Record", multiple.Entities[0].Name)
+	require.Equal(t, "Second Record", multiple.Entities[1].Name)
+}
@@ -8,9 +8,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/moov-io/watchman/internal/clusters"
 	"github.com/moov-io/watchman/internal/indices"
 	"github.com/moov-io/watchman/internal/largest"
+	"github.com/moov-io/watchman/internal/snapshot"
 	"github.com/moov-io/watchman/pkg/search"
 
 	"github.com/moov-io/base/log"
@@ -20,34 +23,150 @@ type Service interface {
 	UpdateEntities(entities []search.Entity[search.Value])
 
 	Search(ctx context.Context, query search.Entity[search.Value], opts SearchOpts) ([]search.SearchedEntity[search.Value], error)
+
+	// Cluster returns the records linked under a cluster ID
+	Cluster(id string) (clusters.Cluster, bool)
+
+	// Lookup returns the entities for each key which is found, in the same order as keys.
+	// Every record is returned when a list publishes several with the same ID. Sources are
+	// compared case-insensitively.
+	// The entities from the last refresh on or before asOf are used when it's a past date.
+	Lookup(keys []EntityKey, asOf *time.Time) ([]search.Entity[search.Value], error)
+}
+
+// EntityKey identifies a record by the list it's from and its ID on that list
+type EntityKey struct {
+	Source   search.SourceList `json:"source"`
+	SourceID string            `json:"sourceID"`
+}
+
+func entityKey(entity search.Entity[search.Value]) EntityKey {
+	return EntityKey{Source: entity.Source, SourceID: entity.SourceID}.normalize()
+}
+
+// normalize lowercases the source, since custom lists keep the case of their configured name
+func (k EntityKey) normalize() EntityKey {
+	k.Source = search.SourceList(strings.ToLower(strings.TrimSpace(string(k.Source))))
+	k.SourceID = strings.TrimSpace(k.SourceID)
+	return k
 }
 
-func NewService(logger log.Logger) Service {
+// History returns the entities which were searched on a past date
+type History interface {
+	EntitiesAsOf(date time.Time) ([]search.Entity[search.Value], error)
+}
+
+// NewService returns a Service. history can be nil when searching past dates isn't supported.
+func NewService(logger log.Logger, history History) Service {
 	return &service{
-		logger: logger,
+		logger:  logger,
+		history: history,
 	}
 }
 
 type service struct {
 	logger   log.Logger
+	history  History
 	entities []search.Entity[search.Value]
+	clusters *clusters.Index
+	byKey    map[EntityKey][]int // indexes into entities
 
-	sync.RWMutex // protects entities
+	sync.RWMutex // protects entities, clusters and byKey
 }
 
 func (s *service) UpdateEntities(entities []search.Entity[search.Value]) {
+	// Link records across lists before they're searched
+	start := time.Now()
+	index := clusters.Resolve(entities)
+	s.logger.Info().Logf("found %d clusters of linked records in %v", index.Len(), time.Since(start))
+
+	var duplicates int
+	byKey := make(map[EntityKey][]int, len(entities))
+	for idx, entity := range entities {
+		key := entityKey(entity)
+		if len(byKey[key]) == 1 {
+			duplicates++
+		}
+		byKey[key] = append(byKey[key], idx)
+	}
+	if duplicates > 0 {
+		s.logger.Warn().Logf("found %d source IDs shared by more than one record", duplicates)
+	}
+
 	s.Lock()
 	defer s.Unlock()
 
 	s.entities = entities
+	s.clusters = index
+	s.byKey = byKey
+}
+
+func (s *service) Cluster(id string) (clusters.Cluster, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.clusters.Get(id)
+}
+
+func (s *service) Lookup(keys []EntityKey, asOf *time.Time) ([]search.Entity[search.Value], error) {
+	if isPastDate(asOf) {
+		if s.history == nil {
+			return nil, fmt.Errorf("entity lookup: %w: searching past dates is not enabled", snapshot.ErrNoHistory)
+		}
+		entities, err := s.history.EntitiesAsOf(*asOf)
+		if err != nil {
+			return nil, fmt.Errorf("entity lookup: %w", err)
+		}
+
+		// Past dates are read rarely, so scan them rather than keeping another map
+		found := make(map[EntityKey][]search.Entity[search.Value], len(keys))
+		for _, key := range keys {
+			found[key.normalize()] = nil
+		}
+		for _, entity := range entities {
+			key := entityKey(entity)
+			if _, wanted := found[key]; wanted {
+				found[key] = append(found[key], entity)
+			}
+		}
+
+		var out []search.Entity[search.Value]
+		for _, key := range keys {
+			out = append(out, found[key.normalize()]...)
+		}
+		return out, nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	var out []search.Entity[search.Value]
+	for _, key := range keys {
+		for _, idx := range s.byKey[key.normalize()] {
+			out = append(out, s.entities[idx])
+		}
+	}
+	return out, nil
 }
 
 func (s *service) Search(ctx context.Context, query search.Entity[search.Value], opts SearchOpts) ([]search.SearchedEntity[search.Value], error) {
+	if isPastDate(opts.AsOf) {
+		if s.history == nil {
+			return nil, fmt.Errorf("v2 search: %w: searching past dates is not enabled", snapshot.ErrNoHistory)
+		}
+		entities, err := s.history.EntitiesAsOf(*opts.AsOf)
+		if err != nil {
+			return nil, fmt.Errorf("v2 search: %w", err)
+		}
+		// Clusters are only resolved for the current entities
+		return s.performSearch(ctx, entities, nil, query, opts)
+	}
+
 	// Grab a read-lock over our data
 	s.RLock()
 	defer s.RUnlock()
 
-	out, err := s.performSearch(ctx, query, opts)
+	out, err := s.performSearch(ctx, s.entities, s.clusters, query, opts)
 	if err != nil {
 		return nil, fmt.Errorf("v2 search: %w", err)
 	}
@@ -58,14 +177,37 @@ type SearchOpts struct {
 	Limit    int
 	MinMatch float64
 
+	// AsOf searches the entities from the last refresh on or before this date.
+	// The current entities are searched when it's nil or today.
+	AsOf *time.Time
+
+	// CollapseClusters returns only the best match from each cluster of linked records
+	CollapseClusters bool
+
+	// Explain includes how each result's match score was calculated
+	Explain bool
+
 	RequestID      string
 	DebugSourceIDs []string
 }
 
-func (s *service) performSearch(ctx context.Context, query search.Entity[search.Value], opts SearchOpts) ([]search.SearchedEntity[search.Value], error) {
-	items := largest.NewItems(opts.Limit, opts.MinMatch)
+// isPastDate returns true for dates before today (in UTC)
+func isPastDate(date *time.Time) bool {
+	if date == nil {
+		return false
+	}
+	return date.Format("2006-01-02") < time.Now().In(time.UTC).Format("2006-01-02")
+}
+
+func (s *service) performSearch(ctx context.Context, entities []search.Entity[search.Value], index *clusters.Index, query search.Entity[search.Value], opts SearchOpts) ([]search.SearchedEntity[search.Value], error) {
+	// Keep extra matches when collapsing so Limit can still be reached
+	capacity := opts.Limit
+	if opts.CollapseClusters && index.Len() > 0 {
+		capacity *= collapseCapacityFactor
+	}
+	items := largest.NewItems(capacity, opts.MinMatch)
 
-	indices.ProcessSliceFn(s.entities, getGroupCount(opts), func(index search.Entity[search.Value]) {
+	indices.ProcessSliceFn(entities, getGroupCount(opts), func(index search.Entity[search.Value]) {
 		score := search.DebugSimilarity(nil, query, index) // TODO(adam): add proper debug functionality?
 
 		if slices.Contains(opts.DebugSourceIDs, index.SourceID) {
@@ -83,6 +225,7 @@ func (s *service) performSearch(ctx context.Context, query search.Entity[search.
 	})
 
 	results := items.Items()
+	seenClusters := make(map[string]bool)
 	var out []search.SearchedEntity[search.Value]
 	// fmt.Printf("results=%d ", len(results))
 
@@ -93,11 +236,29 @@ func (s *service) performSearch(ctx context.Context, query search.Entity[search.
 
 		// fmt.Printf("res: %#v\n", res)
 
+		clusterID, linked := index.Linked(res.Value)
+		if opts.CollapseClusters && clusterID != "" {
+			if seenClusters[clusterID] {
+				continue
+			}
+			seenClusters[clusterID] = true
+		}
+		if len(out) >= opts.Limit {
+			break
+		}
+
 		// fmt.Println("append")
-		out = append(out, search.SearchedEntity[search.Value]{
-			Entity: res.Value,
-			Match:  res.Weight,
-		})
+		entity := search.SearchedEntity[search.Value]{
+			Entity:    res.Value,
+			Match:     res.Weight,
+			ClusterID: clusterID,
+			Linked:    linked,
+		}
+		if opts.Explain {
+			// Only the results are scored again, so searches without explanations don't pay for them
+			_, entity.Explanation = search.ExplainSimilarity(query, res.Value)
+		}
+		out = append(out, entity)
 	}
 	// fmt.Printf("out: %d\n", len(out))
 
@@ -107,6 +268,8 @@ func (s *service) performSearch(ctx context.Context, query search.Entity[search.
 }
 
 const (
+	collapseCapacityFactor = 5
+
 	defaultGroupCount = 20 // rough estimate from local testing // TODO(adam): more benchmarks
 )
 
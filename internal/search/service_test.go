@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/moov-io/watchman/internal/snapshot"
 	"github.com/moov-io/watchman/pkg/ofac"
 	"github.com/moov-io/watchman/pkg/search"
 
@@ -55,6 +57,156 @@ func TestService_Search(t *testing.T) {
 	})
 }
 
+func TestService_SearchClusters(t *testing.T) {
+	ctx := context.Background()
+
+	entity := func(source search.SourceList, sourceID string) search.Entity[search.Value] {
+		return search.Entity[search.Value]{
+			Name:     "Nicolas Maduro Moros",
+			Type:     search.EntityPerson,
+			Source:   source,
+			SourceID: sourceID,
+			Person: &search.Person{
+				Name: "Nicolas Maduro Moros",
+				GovernmentIDs: []search.GovernmentID{
+					{Type: search.GovernmentIDPassport, Identifier: "5892464"},
+				},
+			},
+		}
+	}
+
+	svc := NewService(log.NewTestLogger(), nil)
+	svc.UpdateEntities([]search.Entity[search.Value]{
+		entity(search.SourceUSOFAC, "22790"),
+		entity(search.SourceEUCSL, "13"),
+	})
+
+	query := search.Entity[search.Value]{Name: "Nicolas Maduro", Type: search.EntityPerson, Person: &search.Person{Name: "Nicolas Maduro"}}
+	results, err := svc.Search(ctx, query, SearchOpts{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.NotEmpty(t, results[0].ClusterID)
+	require.Equal(t, results[0].ClusterID, results[1].ClusterID)
+	require.Len(t, results[0].Linked, 1)
+	require.NotEqual(t, results[0].Source, results[0].Linked[0].Source)
+
+	cluster, found := svc.Cluster(results[0].ClusterID)
+	require.True(t, found)
+	require.Len(t, cluster.Entities, 2)
+
+	// collapsed results have one record from each cluster
+	results, err = svc.Search(ctx, query, SearchOpts{Limit: 10, CollapseClusters: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Linked, 1)
+}
+
+type mockHistory struct {
+	entities []search.Entity[search.Value]
+	asOf     time.Time
+}
+
+func (h *mockHistory) EntitiesAsOf(date time.Time) ([]search.Entity[search.Value], error) {
+	h.asOf = date
+	if h.entities == nil {
+		return nil, snapshot.ErrNoHistory
+	}
+	return h.entities, nil
+}
+
+func TestService_SearchAsOf(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewTestLogger()
+
+	current := search.Entity[search.Value]{Name: "JOHN DOE", Type: search.EntityPerson, SourceID: "current", Person: &search.Person{Name: "JOHN DOE"}}
+	previous := search.Entity[search.Value]{Name: "JOHN DOE", Type: search.EntityPerson, SourceID: "previous", Person: &search.Person{Name: "JOHN DOE"}}
+
+	history := &mockHistory{entities: []search.Entity[search.Value]{previous}}
+	svc := NewService(logger, history)
+	svc.UpdateEntities([]search.Entity[search.Value]{current})
+
+	query := search.Entity[search.Value]{Name: "John Doe", Type: search.EntityPerson, Person: &search.Person{Name: "John Doe"}}
+	opts := SearchOpts{Limit: 1}
+
+	results, err := svc.Search(ctx, query, opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "current", results[0].SourceID)
+
+	// today searches the current entities
+	today := time.Now()
+	opts.AsOf = &today
+	results, err = svc.Search(ctx, query, opts)
+	require.NoError(t, err)
+	require.Equal(t, "current", results[0].SourceID)
+	require.True(t, history.asOf.IsZero())
+
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	opts.AsOf = &lastWeek
+	results, err = svc.Search(ctx, query, opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "previous", results[0].SourceID)
+	require.True(t, lastWeek.Equal(history.asOf))
+
+	history.entities = nil
+	_, err = svc.Search(ctx, query, opts)
+	require.ErrorIs(t, err, snapshot.ErrNoHistory)
+
+	// without history past dates can't be searched
+	_, err = NewService(logger, nil).Search(ctx, query, opts)
+	require.ErrorIs(t, err, snapshot.ErrNoHistory)
+}
+
+func TestService_Lookup(t *testing.T) {
+	entity := func(source search.SourceList, sourceID, name string) search.Entity[search.Value] {
+		return search.Entity[search.Value]{Name: name, Type: search.EntityPerson, Source: source, SourceID: sourceID}
+	}
+
+	history := &mockHistory{entities: []search.Entity[search.Value]{
+		entity(search.SourceUSOFAC, "1", "Former Name"),
+		entity(search.SourceUSOFAC, "9", "Removed Person"),
+	}}
+	svc := NewService(log.NewTestLogger(), history)
+	svc.UpdateEntities([]search.Entity[search.Value]{
+		entity(search.SourceUSOFAC, "1", "John Doe"),
+		entity(search.SourceEUCSL, "1", "Jane Doe"),
+		entity(search.SourceUSCSL, "7", "First Record"),
+		entity(search.SourceUSCSL, "7", "Second Record"),
+	})
+
+	found, err := svc.Lookup([]EntityKey{
+		{Source: search.SourceEUCSL, SourceID: "1"},
+		{Source: search.SourceUSOFAC, SourceID: "9"},
+		{Source: search.SourceUSOFAC, SourceID: "1"},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, "Jane Doe", found[0].Name)
+	require.Equal(t, "John Doe", found[1].Name)
+
+	// every record sharing an ID is returned
+	found, err = svc.Lookup([]EntityKey{{Source: search.SourceUSCSL, SourceID: "7"}}, nil)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, "First Record", found[0].Name)
+	require.Equal(t, "Second Record", found[1].Name)
+
+	// records removed since a past date can still be found
+	lastMonth := time.Now().AddDate(0, -1, 0)
+	found, err = svc.Lookup([]EntityKey{
+		{Source: search.SourceUSOFAC, SourceID: "9"},
+		{Source: search.SourceUSOFAC, SourceID: "1"},
+	}, &lastMonth)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, "Removed Person", found[0].Name)
+	require.Equal(t, "Former Name", found[1].Name)
+
+	_, err = NewService(log.NewTestLogger(), nil).Lookup(nil, &lastMonth)
+	require.ErrorIs(t, err, snapshot.ErrNoHistory)
+}
+
 func testService(tb testing.TB) Service {
 	files := testInputs(tb,
 		filepath.Join("..", "..", "pkg", "ofac", "testdata", "sdn.csv"),
@@ -69,7 +221,7 @@ func testService(tb testing.TB) Service {
 
 	logger := log.NewTestLogger()
 
-	svc := NewService(logger)
+	svc := NewService(logger, nil)
 	svc.UpdateEntities(entities)
 
 	return svc
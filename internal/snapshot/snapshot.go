@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/csl_eu"
+	"github.com/moov-io/watchman/pkg/csl_uk"
+	"github.com/moov-io/watchman/pkg/csl_us"
+	"github.com/moov-io/watchman/pkg/custom_list"
+	"github.com/moov-io/watchman/pkg/dpl"
+	"github.com/moov-io/watchman/pkg/ofac"
+	"github.com/moov-io/watchman/pkg/opensanctions"
+	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/un_csl"
+)
+
+type Config struct {
+	// Directory is where snapshots are written after each refresh. Snapshots are disabled when empty.
+	Directory string
+
+	// HistoryDays is how many days of snapshots are kept for searching with asOf. History is disabled when zero.
+	HistoryDays int
+}
+
+// Snapshot is the merged set of entities from a refresh
+type Snapshot struct {
+	Version   int
+	CreatedAt time.Time
+
+	Lists    map[string]int
+	Entities []search.Entity[search.Value]
+}
+
+const (
+	// Version is increased whenever search.Entity or a list's SourceData changes in a way
+	// older snapshots can't be decoded into.
+	Version = 1
+
+	// Filename is the name of the most recent snapshot within Config.Directory
+	Filename = "entities.snapshot"
+
+	magic = "WATCHMAN-SNAPSHOT\n"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+)
+
+func init() {
+	// SourceData is an interface, so gob needs each concrete type. Registered sources add their own with sources.RegisterType.
+	gob.Register(csl_eu.CSLRecord{})
+	gob.Register(csl_uk.CSLRecord{})
+	gob.Register(csl_uk.SanctionsListRecord{})
+	gob.Register(csl_us.EL{})
+	gob.Register(csl_us.MEU{})
+	gob.Register(csl_us.SSI{})
+	gob.Register(csl_us.UVL{})
+	gob.Register(csl_us.FSE{})
+	gob.Register(csl_us.ISN{})
+	gob.Register(csl_us.PLC{})
+	gob.Register(csl_us.CAP{})
+	gob.Register(csl_us.DTC{})
+	gob.Register(csl_us.CMIC{})
+	gob.Register(csl_us.NS_MBS{})
+	gob.Register(custom_list.Record{})
+	gob.Register(dpl.DPL{})
+	gob.Register(ofac.SDN{})
+	gob.Register(ofac.DistinctParty{})
+	gob.Register(opensanctions.Entity{})
+	gob.Register(un_csl.Individual{})
+	gob.Register(un_csl.Entity{})
+}
+
+// Encode writes a gzip compressed snapshot
+func Encode(w io.Writer, snap Snapshot) error {
+	snap.Version = Version
+
+	if _, err := io.WriteString(w, magic); err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := gob.NewEncoder(gz).Encode(snap); err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+	return gz.Close()
+}
+
+// Decode reads a snapshot written by Encode
+func Decode(r io.Reader) (*Snapshot, error) {
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != magic {
+		return nil, errors.New("not a watchman snapshot")
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	var snap Snapshot
+	if err := gob.NewDecoder(gz).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	// Read through the gzip trailer so truncated or corrupt files are caught by its checksum
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	if snap.Version != Version {
+		return nil, fmt.Errorf("%w: %d (expected %d)", ErrUnsupportedVersion, snap.Version, Version)
+	}
+	return &snap, nil
+}
+
+// Write saves the snapshot as Filename in dir. The previous snapshot is replaced once the new one is fully written.
+func Write(dir string, snap Snapshot) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	fd, err := os.CreateTemp(dir, Filename+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	defer os.Remove(fd.Name()) // cleanup when the rename doesn't happen
+
+	buf := bufio.NewWriter(fd)
+	if err := Encode(buf, snap); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		fd.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := fd.Close(); err != nil {
+		return fmt.Errorf("closing snapshot: %w", err)
+	}
+
+	return os.Rename(fd.Name(), filepath.Join(dir, Filename))
+}
+
+// Read loads the snapshot from dir. An error wrapping os.ErrNotExist is returned when there isn't one.
+func Read(dir string) (*Snapshot, error) {
+	fd, err := os.Open(filepath.Join(dir, Filename))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return Decode(bufio.NewReader(fd))
+}
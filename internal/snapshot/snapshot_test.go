@@ -0,0 +1,159 @@
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/internal/changes"
+	"github.com/moov-io/watchman/pkg/csl_eu"
+	"github.com/moov-io/watchman/pkg/csl_uk"
+	"github.com/moov-io/watchman/pkg/csl_us"
+	"github.com/moov-io/watchman/pkg/custom_list"
+	"github.com/moov-io/watchman/pkg/dpl"
+	"github.com/moov-io/watchman/pkg/ofac"
+	"github.com/moov-io/watchman/pkg/opensanctions"
+	"github.com/moov-io/watchman/pkg/search"
+	"github.com/moov-io/watchman/pkg/sources"
+	"github.com/moov-io/watchman/pkg/un_csl"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEntities(t *testing.T) []search.Entity[search.Value] {
+	t.Helper()
+
+	var out []search.Entity[search.Value]
+
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", "csl.csv"))
+	require.NoError(t, err)
+	cslUS, err := csl_us.ReadFile(fd)
+	require.NoError(t, err)
+	out = append(out, csl_us.ConvertSanctionsData(cslUS)...)
+
+	fd, err = os.Open(filepath.Join("..", "..", "pkg", "ofac", "testdata", "sdn_advanced.xml"))
+	require.NoError(t, err)
+	advanced, err := ofac.ReadAdvanced(fd)
+	require.NoError(t, err)
+	out = append(out, ofac.GroupAdvancedIntoEntities(advanced)...)
+
+	out = append(out, ofac.ToEntity(ofac.SDN{EntityID: "123", SDNName: "JOHN DOE", SDNType: "individual"}, nil, nil, nil))
+
+	fd, err = os.Open(filepath.Join("..", "..", "pkg", "un_csl", "testdata", "consolidated.xml"))
+	require.NoError(t, err)
+	unCSL, err := un_csl.ReadFile(fd)
+	require.NoError(t, err)
+	out = append(out, un_csl.ConvertSanctionsData(unCSL)...)
+
+	fd, err = os.Open(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+	dplRecords, err := dpl.ReadFile(fd)
+	require.NoError(t, err)
+	out = append(out, dpl.ConvertSanctionsData(dplRecords[:10])...)
+
+	osEntities, err := opensanctions.ReadDir(filepath.Join("..", "..", "pkg", "opensanctions", "testdata"))
+	require.NoError(t, err)
+	out = append(out, opensanctions.ConvertEntities(osEntities)...)
+
+	records, err := custom_list.ReadDir(filepath.Join("..", "..", "pkg", "custom_list", "testdata", "fraud"))
+	require.NoError(t, err)
+	out = append(out, custom_list.ConvertRecords("internal", nil, records)...)
+
+	out = append(out, csl_eu.ToEntity(csl_eu.CSLRecord{EntityLogicalID: 13, NameAliasWholeNames: []string{"Saddam Hussein Al-Tikriti"}}))
+	out = append(out, csl_uk.CSLToEntity(csl_uk.CSLRecord{GroupID: 1234, Names: []string{"HAJI KHAIRULLAH HAJI SATTAR MONEY EXCHANGE"}}))
+	out = append(out, csl_uk.SanctionsListToEntity(csl_uk.SanctionsListRecord{UniqueID: "AFG0001", Names: []string{"HAJI KHAIRULLAH"}}))
+
+	return out
+}
+
+func TestEncodeDecode(t *testing.T) {
+	entities := testEntities(t)
+
+	snap := Snapshot{
+		CreatedAt: time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Lists:     map[string]int{"us_csl": 10},
+		Entities:  entities,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, snap))
+
+	found, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, Version, found.Version)
+	require.True(t, snap.CreatedAt.Equal(found.CreatedAt))
+	require.Equal(t, snap.Lists, found.Lists)
+	require.Len(t, found.Entities, len(entities))
+
+	// SourceData keeps its original type, so nothing appears to have changed
+	diffs, err := changes.Diff(entities, found.Entities)
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+
+	_, isRecord := found.Entities[len(found.Entities)-1].SourceData.(csl_uk.SanctionsListRecord)
+	require.True(t, isRecord)
+}
+
+// customRecord is SourceData from a registered source
+type customRecord struct {
+	AccountID string
+	Reason    string
+}
+
+func TestEncodeDecode_RegisteredType(t *testing.T) {
+	sources.RegisterType(customRecord{})
+
+	snap := Snapshot{
+		Entities: []search.Entity[search.Value]{
+			{
+				Name:       "John Doe",
+				Type:       search.EntityPerson,
+				Source:     "internal_fraud",
+				SourceID:   "1",
+				SourceData: customRecord{AccountID: "12345", Reason: "chargebacks"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, snap))
+
+	found, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, found.Entities, 1)
+	require.Equal(t, customRecord{AccountID: "12345", Reason: "chargebacks"}, found.Entities[0].SourceData)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte("hello, world")))
+	require.ErrorContains(t, err, "not a watchman snapshot")
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, Snapshot{}))
+	data := buf.Bytes()
+	_, err = Decode(bytes.NewReader(data[:len(data)-10]))
+	require.Error(t, err)
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Read(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	entities := testEntities(t)[:5]
+	require.NoError(t, Write(dir, Snapshot{Entities: entities}))
+	require.NoError(t, Write(dir, Snapshot{Entities: entities[:2]}))
+
+	found, err := Read(dir)
+	require.NoError(t, err)
+	require.Len(t, found.Entities, 2)
+
+	// only the snapshot remains, temporary files are cleaned up
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, Filename, files[0].Name())
+}
@@ -0,0 +1,196 @@
+package snapshot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+const (
+	historyDirectory = "history"
+	historyExtension = ".snapshot"
+	dateFormat       = "2006-01-02"
+)
+
+var (
+	ErrNoHistory = errors.New("no snapshot found")
+)
+
+// Store saves the snapshot of each refresh and keeps a dated copy of the last refresh
+// on each day for HistoryDays. Dated copies stay on disk and are read when searched.
+type Store struct {
+	conf Config
+
+	mu         sync.Mutex
+	cachedDate string
+	cached     []search.Entity[search.Value]
+}
+
+func NewStore(conf Config) *Store {
+	return &Store{
+		conf: conf,
+	}
+}
+
+// Enabled returns true when a Directory is configured
+func (s *Store) Enabled() bool {
+	return s != nil && s.conf.Directory != ""
+}
+
+// Latest reads the most recent snapshot. An error wrapping os.ErrNotExist is returned when there isn't one.
+func (s *Store) Latest() (*Snapshot, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("snapshots are disabled: %w", os.ErrNotExist)
+	}
+	return Read(s.conf.Directory)
+}
+
+// Save writes the snapshot and, when HistoryDays is set, replaces the dated copy
+// for the day it was created on and removes copies older than HistoryDays.
+func (s *Store) Save(snap Snapshot) error {
+	if !s.Enabled() {
+		return nil
+	}
+	if err := Write(s.conf.Directory, snap); err != nil {
+		return err
+	}
+	if s.conf.HistoryDays <= 0 {
+		return nil
+	}
+
+	dir := filepath.Join(s.conf.Directory, historyDirectory)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating snapshot history directory: %w", err)
+	}
+
+	date := snap.CreatedAt.In(time.UTC).Format(dateFormat)
+	if err := copyFile(filepath.Join(s.conf.Directory, Filename), filepath.Join(dir, date+historyExtension)); err != nil {
+		return fmt.Errorf("saving %s snapshot: %w", date, err)
+	}
+
+	s.mu.Lock()
+	if s.cachedDate == date {
+		s.cachedDate, s.cached = "", nil
+	}
+	s.mu.Unlock()
+
+	return s.prune(snap.CreatedAt)
+}
+
+// prune removes dated copies which are older than HistoryDays
+func (s *Store) prune(now time.Time) error {
+	dates, err := s.dates()
+	if err != nil {
+		return err
+	}
+	cutoff := now.In(time.UTC).AddDate(0, 0, -s.conf.HistoryDays).Format(dateFormat)
+	for _, date := range dates {
+		if date < cutoff {
+			if err := os.Remove(filepath.Join(s.conf.Directory, historyDirectory, date+historyExtension)); err != nil {
+				return fmt.Errorf("removing %s snapshot: %w", date, err)
+			}
+		}
+	}
+	return nil
+}
+
+// dates returns the date of each saved copy, oldest first
+func (s *Store) dates() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.conf.Directory, historyDirectory))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var out []string
+	for _, entry := range entries {
+		date, found := strings.CutSuffix(entry.Name(), historyExtension)
+		if !found || !entry.Type().IsRegular() {
+			continue
+		}
+		if _, err := time.Parse(dateFormat, date); err == nil {
+			out = append(out, date)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// EntitiesAsOf returns the entities from the last refresh on or before the date.
+// ErrNoHistory is returned when there's no saved copy from then.
+func (s *Store) EntitiesAsOf(asOf time.Time) ([]search.Entity[search.Value], error) {
+	if !s.Enabled() || s.conf.HistoryDays <= 0 {
+		return nil, fmt.Errorf("%w: snapshot history is disabled", ErrNoHistory)
+	}
+
+	dates, err := s.dates()
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+
+	wanted := asOf.Format(dateFormat)
+	idx := sort.SearchStrings(dates, wanted)
+	if idx < len(dates) && dates[idx] == wanted {
+		idx++
+	}
+	if idx == 0 {
+		return nil, fmt.Errorf("%w as of %s", ErrNoHistory, wanted)
+	}
+	date := dates[idx-1]
+
+	// Only the most recently searched copy is kept in memory
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cachedDate == date {
+		return s.cached, nil
+	}
+
+	fd, err := os.Open(filepath.Join(s.conf.Directory, historyDirectory, date+historyExtension))
+	if err != nil {
+		return nil, fmt.Errorf("opening %s snapshot: %w", date, err)
+	}
+	defer fd.Close()
+
+	snap, err := Decode(bufio.NewReader(fd))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s snapshot: %w", date, err)
+	}
+
+	s.cachedDate, s.cached = date, snap.Entities
+	return snap.Entities, nil
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(filepath.Dir(to), filepath.Base(to)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dst.Name()) // cleanup when the rename doesn't happen
+
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(dst.Name(), to)
+}
@@ -0,0 +1,80 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Disabled(t *testing.T) {
+	var store *Store
+	require.False(t, store.Enabled())
+	require.NoError(t, store.Save(Snapshot{}))
+
+	_, err := store.Latest()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	store = NewStore(Config{Directory: t.TempDir()})
+	_, err = store.EntitiesAsOf(time.Now())
+	require.ErrorIs(t, err, ErrNoHistory)
+}
+
+func TestStore_History(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(Config{Directory: dir, HistoryDays: 3})
+
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 12, 0, 0, 0, time.UTC)
+	}
+	entities := func(names ...string) []search.Entity[search.Value] {
+		var out []search.Entity[search.Value]
+		for _, name := range names {
+			out = append(out, search.Entity[search.Value]{Name: name, Type: search.EntityPerson, SourceID: name})
+		}
+		return out
+	}
+
+	require.NoError(t, store.Save(Snapshot{CreatedAt: day(1), Entities: entities("a")}))
+	require.NoError(t, store.Save(Snapshot{CreatedAt: day(2), Entities: entities("a", "b")}))
+	// the last refresh of a day replaces its earlier copy
+	require.NoError(t, store.Save(Snapshot{CreatedAt: day(2).Add(time.Hour), Entities: entities("a", "b", "c")}))
+
+	latest, err := store.Latest()
+	require.NoError(t, err)
+	require.Len(t, latest.Entities, 3)
+
+	found, err := store.EntitiesAsOf(day(1))
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	found, err = store.EntitiesAsOf(day(2))
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	// days without a refresh use the copy before them
+	found, err = store.EntitiesAsOf(day(9))
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	_, err = store.EntitiesAsOf(time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrNoHistory)
+
+	// copies older than HistoryDays are removed
+	require.NoError(t, store.Save(Snapshot{CreatedAt: day(5), Entities: entities("d")}))
+
+	dates, err := store.dates()
+	require.NoError(t, err)
+	require.Equal(t, []string{"2024-03-02", "2024-03-05"}, dates)
+
+	_, err = store.EntitiesAsOf(day(1))
+	require.ErrorIs(t, err, ErrNoHistory)
+
+	files, err := os.ReadDir(filepath.Join(dir, historyDirectory))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
@@ -15,44 +15,60 @@ type CSLRecord struct {
 	EntityRemark               string            `json:"entityRemark"`
 	EntitySubjectType          string            `json:"entitySubjectType"`
 	EntityPublicationURL       string            `json:"entityPublicationURL"`
+	EntityPublicationDate      string            `json:"entityPublicationDate"`
 	EntityReferenceNumber      string            `json:"entityReferenceNumber"`
+	EntityRegulationType       string            `json:"entityRegulationType"`
+	EntityRegulationProgramme  string            `json:"entityRegulationProgramme"`
 	NameAliasWholeNames        []string          `json:"nameAliasWholeNames"`
 	NameAliasTitles            []string          `json:"nameAliasTitles"`
+	NameAliasGender            string            `json:"nameAliasGender"`
 	AddressCities              []string          `json:"addressCities"`
 	AddressStreets             []string          `json:"addressStreets"`
 	AddressPoBoxes             []string          `json:"addressPoBoxs"`
 	AddressZipCodes            []string          `json:"addressZipCodes"`
 	AddressCountryDescriptions []string          `json:"addressCountryDescriptions"`
+	Addresses                  []Address         `json:"addresses"`
 	BirthDates                 []string          `json:"birthDates"`
 	BirthCities                []string          `json:"birthCities"`
 	BirthCountries             []string          `json:"birthCountries"`
+	Identifications            []Identification  `json:"identifications"`
 	ValidFromTo                map[string]string `json:"validFromTo"`
 }
 
 // header indicies
 const (
-	FileGenerationDateIdx             = 0
-	EntityLogicalIdx                  = 1
-	ReferenceNumberIdx                = 2
-	EntityRemarkIdx                   = 6
-	EntitySubjectTypeIdx              = 8
-	EntityRegulationPublicationURLIdx = 15
+	FileGenerationDateIdx              = 0
+	EntityLogicalIdx                   = 1
+	ReferenceNumberIdx                 = 2
+	EntityRemarkIdx                    = 6
+	EntitySubjectTypeIdx               = 8
+	EntityRegulationTypeIdx            = 9
+	EntityRegulationPublicationDateIdx = 11
+	EntityRegulationProgrammeIdx       = 14
+	EntityRegulationPublicationURLIdx  = 15
 
 	NameAliasWholeNameIdx = 19
+	NameAliasGenderIdx    = 21
 	NameAliasTitleIdx     = 22
 
 	AddressCityIdx               = 34
 	AddressStreetIdx             = 35
 	AddressPoBoxIdx              = 36
 	AddressZipCodeIdx            = 37
+	AddressCountryIso2CodeIdx    = 42
 	AddressCountryDescriptionIdx = 43
 
 	BirthDateIdx        = 54
 	BirthDateCityIdx    = 65
 	BirthDateCountryIdx = 67
 
-	IdentificationValidFromIdx = 86
-	IdentificationValidToIdx   = 87
+	IdentificationNumberIdx             = 78
+	IdentificationValidFromIdx          = 86
+	IdentificationValidToIdx            = 87
+	IdentificationTypeCodeIdx           = 90
+	IdentificationTypeDescriptionIdx    = 91
+	IdentificationCountryIso2CodeIdx    = 93
+	IdentificationCountryDescriptionIdx = 94
 )
 
 // below is the original struct used to parse the document
@@ -115,7 +131,7 @@ type Address struct { // addresses
 	// Place              string
 	// AsAtListingTime    string
 	// ContactInfo        string
-	// CountryIso2code    string
+	CountryIso2code    string `json:"countryIso2code"`
 	CountryDescription string `json:"countryDescription"`
 	// LogicalID          int64
 	// RegulationLanguage string
@@ -143,7 +159,7 @@ type BirthDate struct {
 
 type Identification struct {
 	// Regulation         *Regulation
-	// Number             int64
+	Number string `json:"number"`
 	// KnownExpired       bool
 	// KnownFalse         bool
 	// ReportedLost       bool
@@ -155,11 +171,11 @@ type Identification struct {
 	ValidFrom string `json:"validFrom"`
 	ValidTo   string `json:"validTo"`
 	// NameOnDocument     string
-	// TypeCode           string
-	// TypeDescription    string
+	TypeCode        string `json:"typeCode"`
+	TypeDescription string `json:"typeDescription"`
 	// Region             string
-	// CountryIso2code    string
-	// CountryDescription string
+	CountryIso2code    string `json:"countryIso2code"`
+	CountryDescription string `json:"countryDescription"`
 	// RegulationLanguage string
 	// Remark             string
 }
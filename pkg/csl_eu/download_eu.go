@@ -24,8 +24,9 @@ var (
 	euDownloadURL = strx.Or(os.Getenv("EU_CSL_DOWNLOAD_URL"), publicEUDownloadURL)
 )
 
-func DownloadEU(ctx context.Context, logger log.Logger, initialDir string) (map[string]io.ReadCloser, error) {
+func DownloadEU(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	euCSLNameAndSource := make(map[string]string)
 	euCSLNameAndSource["eu_csl.csv"] = euDownloadURL
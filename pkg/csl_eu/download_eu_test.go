@@ -13,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+
 	"github.com/moov-io/base/log"
 )
 
@@ -21,7 +23,7 @@ func TestEUDownload(t *testing.T) {
 		return
 	}
 
-	file, err := DownloadEU(context.Background(), log.NewNopLogger(), "")
+	file, err := DownloadEU(context.Background(), log.NewNopLogger(), "", download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -54,7 +56,7 @@ func TestEUDownload_initialDir(t *testing.T) {
 	// create each file
 	mk(t, "eu_csl.csv", "file=eu_csl.csv")
 
-	file, err := DownloadEU(context.Background(), log.NewNopLogger(), dir)
+	file, err := DownloadEU(context.Background(), log.NewNopLogger(), dir, download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
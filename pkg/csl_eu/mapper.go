@@ -5,41 +5,165 @@
 package csl_eu
 
 import (
+	"cmp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/moov-io/watchman/pkg/search"
 )
 
-func PtrToEntity(record *CSLRecord) search.Entity[CSLRecord] {
+// ConvertSanctionsData maps each EU CSL record into a search.Entity
+func ConvertSanctionsData(records []CSLRecord) []search.Entity[search.Value] {
+	out := make([]search.Entity[search.Value], 0, len(records))
+	for _, record := range records {
+		out = append(out, ToEntity(record))
+	}
+	return out
+}
+
+func PtrToEntity(record *CSLRecord) search.Entity[search.Value] {
 	if record != nil {
 		return ToEntity(*record)
 	}
-	return search.Entity[CSLRecord]{}
+	return search.Entity[search.Value]{}
 }
 
-func ToEntity(record CSLRecord) search.Entity[CSLRecord] {
-	out := search.Entity[CSLRecord]{
+func ToEntity(record CSLRecord) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
 		Source:     search.SourceEUCSL,
+		SourceID:   strconv.Itoa(record.EntityLogicalID),
 		SourceData: record,
 	}
 
-	if strings.EqualFold(record.EntitySubjectType, "person") {
-		out.Type = search.EntityPerson
-		out.Person = &search.Person{}
+	var altNames []string
+	if len(record.NameAliasWholeNames) > 0 {
+		out.Name = record.NameAliasWholeNames[0]
+		altNames = record.NameAliasWholeNames[1:]
+	}
 
-		if len(record.NameAliasWholeNames) > 0 {
-			out.Name = record.NameAliasWholeNames[0]
-			out.Person.Name = record.NameAliasWholeNames[0]
-			out.Person.AltNames = record.NameAliasWholeNames[1:]
+	switch strings.ToLower(strings.TrimSpace(record.EntitySubjectType)) {
+	case "person":
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:          out.Name,
+			AltNames:      altNames,
+			Gender:        mapGender(record.NameAliasGender),
+			Titles:        record.NameAliasTitles,
+			GovernmentIDs: mapGovernmentIDs(record.Identifications),
 		}
-		if len(record.BirthDates) > 0 {
-			tt, err := time.Parse("2006-01-02", record.BirthDates[0])
-			if err == nil {
-				out.Person.BirthDate = &tt
+		for _, dob := range record.BirthDates {
+			if tt := parseDate(dob); tt != nil {
+				out.Person.BirthDate = tt
+				break
 			}
 		}
+		out.Titles = record.NameAliasTitles
+
+	case "enterprise":
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:       out.Name,
+			AltNames:   altNames,
+			Identifier: mapIdentifiers(record.Identifications),
+		}
 	}
 
+	out.Addresses = mapAddresses(record.Addresses)
+	out.SanctionsInfo = mapSanctionsInfo(record)
+
 	return out
 }
+
+var (
+	dateFormats = []string{"2006-01-02", "2006-01", "2006"}
+)
+
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, format := range dateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return &tt
+		}
+	}
+	return nil
+}
+
+func mapGender(value string) search.Gender {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "M":
+		return search.GenderMale
+	case "F":
+		return search.GenderFemale
+	}
+	return search.GenderUnknown
+}
+
+func mapGovernmentIDs(idents []Identification) []search.GovernmentID {
+	var out []search.GovernmentID
+	for _, ident := range idents {
+		var idType search.GovernmentIDType
+		switch strings.ToLower(ident.TypeCode) {
+		case "passport":
+			idType = search.GovernmentIDPassport
+		case "id":
+			idType = search.GovernmentIDNational
+		case "fiscalcode", "tax":
+			idType = search.GovernmentIDTax
+		case "ssn":
+			idType = search.GovernmentIDSSN
+		case "birthcertificate":
+			idType = search.GovernmentIDBirthCert
+		case "driverslicense":
+			idType = search.GovernmentIDDriversLicense
+		default:
+			idType = search.GovernmentIDPersonalID
+		}
+		out = append(out, search.GovernmentID{
+			Type:       idType,
+			Country:    cmp.Or(ident.CountryIso2code, ident.CountryDescription),
+			Identifier: ident.Number,
+		})
+	}
+	return out
+}
+
+func mapIdentifiers(idents []Identification) []search.Identifier {
+	var out []search.Identifier
+	for _, ident := range idents {
+		out = append(out, search.Identifier{
+			Name:       cmp.Or(ident.TypeDescription, ident.TypeCode),
+			Country:    cmp.Or(ident.CountryIso2code, ident.CountryDescription),
+			Identifier: ident.Number,
+		})
+	}
+	return out
+}
+
+func mapAddresses(addrs []Address) []search.Address {
+	var out []search.Address
+	for _, addr := range addrs {
+		out = append(out, search.Address{
+			Line1:      addr.Street,
+			Line2:      addr.PoBox,
+			City:       addr.City,
+			PostalCode: addr.ZipCode,
+			Country:    cmp.Or(addr.CountryIso2code, addr.CountryDescription),
+		})
+	}
+	return out
+}
+
+func mapSanctionsInfo(record CSLRecord) *search.SanctionsInfo {
+	if record.EntityRegulationProgramme == "" && record.EntityRemark == "" {
+		return nil
+	}
+	info := &search.SanctionsInfo{
+		Description: record.EntityRemark,
+	}
+	if record.EntityRegulationProgramme != "" {
+		info.Programs = []string{record.EntityRegulationProgramme}
+	}
+	return info
+}
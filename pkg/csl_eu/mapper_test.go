@@ -0,0 +1,82 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package csl_eu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToEntity__Person(t *testing.T) {
+	record := CSLRecord{
+		EntityLogicalID:           13,
+		EntityRemark:              "(UNSC RESOLUTION 1483)",
+		EntitySubjectType:         "person",
+		EntityRegulationProgramme: "IRQ",
+		NameAliasWholeNames:       []string{"Saddam Hussein Al-Tikriti", "Abu Ali", "Abou Ali"},
+		NameAliasGender:           "M",
+		BirthDates:                []string{"1937-04-28"},
+		Addresses: []Address{
+			{City: "Baghdad", Street: "Palace Rd", CountryIso2code: "IQ", CountryDescription: "IRAQ"},
+		},
+		Identifications: []Identification{
+			{Number: "A123456", TypeCode: "passport", CountryIso2code: "IQ"},
+		},
+	}
+
+	entity := ToEntity(record)
+	require.Equal(t, "Saddam Hussein Al-Tikriti", entity.Name)
+	require.Equal(t, search.EntityPerson, entity.Type)
+	require.Equal(t, search.SourceEUCSL, entity.Source)
+	require.Equal(t, "13", entity.SourceID)
+
+	require.NotNil(t, entity.Person)
+	require.Equal(t, []string{"Abu Ali", "Abou Ali"}, entity.Person.AltNames)
+	require.Equal(t, search.GenderMale, entity.Person.Gender)
+	require.Equal(t, "1937-04-28", entity.Person.BirthDate.Format(time.DateOnly))
+
+	expectedIDs := []search.GovernmentID{
+		{Type: search.GovernmentIDPassport, Country: "IQ", Identifier: "A123456"},
+	}
+	require.Equal(t, expectedIDs, entity.Person.GovernmentIDs)
+
+	expectedAddresses := []search.Address{
+		{Line1: "Palace Rd", City: "Baghdad", Country: "IQ"},
+	}
+	require.Equal(t, expectedAddresses, entity.Addresses)
+
+	require.NotNil(t, entity.SanctionsInfo)
+	require.Equal(t, []string{"IRQ"}, entity.SanctionsInfo.Programs)
+	require.Equal(t, "(UNSC RESOLUTION 1483)", entity.SanctionsInfo.Description)
+}
+
+func TestToEntity__Enterprise(t *testing.T) {
+	record := CSLRecord{
+		EntityLogicalID:     2012,
+		EntitySubjectType:   "enterprise",
+		NameAliasWholeNames: []string{"Example Shipping LLC", "Example Shipping"},
+		Identifications: []Identification{
+			{Number: "1027700499903", TypeCode: "regnumber", TypeDescription: "Registration Number", CountryIso2code: "RU"},
+		},
+	}
+
+	entity := ToEntity(record)
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Nil(t, entity.Person)
+	require.Nil(t, entity.SanctionsInfo)
+
+	require.NotNil(t, entity.Business)
+	require.Equal(t, "Example Shipping LLC", entity.Business.Name)
+	require.Equal(t, []string{"Example Shipping"}, entity.Business.AltNames)
+
+	expected := []search.Identifier{
+		{Name: "Registration Number", Country: "RU", Identifier: "1027700499903"},
+	}
+	require.Equal(t, expected, entity.Business.Identifier)
+}
@@ -9,12 +9,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 )
 
 func ParseEU(r io.ReadCloser) ([]CSLRecord, CSL, error) {
+	records, report, _, err := parseEU(r)
+	return records, report, err
+}
+
+// ParseEUWithSkipped is ParseEU which also returns how many malformed rows were skipped
+func ParseEUWithSkipped(r io.ReadCloser) ([]CSLRecord, int, error) {
+	records, _, skipped, err := parseEU(r)
+	return records, skipped, err
+}
+
+func parseEU(r io.ReadCloser) ([]CSLRecord, CSL, int, error) {
 	if r == nil {
-		return nil, nil, errors.New("EU CSL file is empty or missing")
+		return nil, nil, 0, errors.New("EU CSL file is empty or missing")
 	}
 	defer r.Close()
 	reader := csv.NewReader(r)
@@ -27,8 +39,9 @@ func ParseEU(r io.ReadCloser) ([]CSLRecord, CSL, error) {
 	report := make(CSL)
 	_, err := reader.Read()
 	if err != nil {
-		return nil, report, fmt.Errorf("failed to read csv: %w", err)
+		return nil, report, 0, fmt.Errorf("failed to read csv: %w", err)
 	}
+	var skipped int
 	for {
 		record, err := reader.Read()
 		if err != nil {
@@ -40,9 +53,10 @@ func ParseEU(r io.ReadCloser) ([]CSLRecord, CSL, error) {
 			if errors.Is(err, csv.ErrFieldCount) ||
 				errors.Is(err, csv.ErrBareQuote) ||
 				errors.Is(err, csv.ErrQuote) {
+				skipped++
 				continue
 			}
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
 		if len(record) <= 1 {
@@ -69,7 +83,7 @@ func ParseEU(r io.ReadCloser) ([]CSLRecord, CSL, error) {
 	for _, row := range report {
 		totalReport = append(totalReport, *row)
 	}
-	return totalReport, report, nil
+	return totalReport, report, skipped, nil
 }
 
 func unmarshalRecord(csvRecord []string, euCSLRecord *CSLRecord) {
@@ -91,6 +105,15 @@ func unmarshalRecord(csvRecord []string, euCSLRecord *CSLRecord) {
 	if csvRecord[EntityRegulationPublicationURLIdx] != "" {
 		euCSLRecord.EntityPublicationURL = csvRecord[EntityRegulationPublicationURLIdx]
 	}
+	if csvRecord[EntityRegulationPublicationDateIdx] != "" {
+		euCSLRecord.EntityPublicationDate = csvRecord[EntityRegulationPublicationDateIdx]
+	}
+	if csvRecord[EntityRegulationTypeIdx] != "" {
+		euCSLRecord.EntityRegulationType = csvRecord[EntityRegulationTypeIdx]
+	}
+	if csvRecord[EntityRegulationProgrammeIdx] != "" {
+		euCSLRecord.EntityRegulationProgramme = csvRecord[EntityRegulationProgrammeIdx]
+	}
 
 	// name alias
 	if csvRecord[NameAliasWholeNameIdx] != "" {
@@ -103,6 +126,9 @@ func unmarshalRecord(csvRecord []string, euCSLRecord *CSLRecord) {
 			euCSLRecord.NameAliasTitles = append(euCSLRecord.NameAliasTitles, csvRecord[NameAliasTitleIdx])
 		}
 	}
+	if csvRecord[NameAliasGenderIdx] != "" && euCSLRecord.NameAliasGender == "" {
+		euCSLRecord.NameAliasGender = csvRecord[NameAliasGenderIdx]
+	}
 	// address
 	if csvRecord[AddressCityIdx] != "" {
 		if !arrayContains(euCSLRecord.AddressCities, csvRecord[AddressCityIdx]) {
@@ -130,6 +156,19 @@ func unmarshalRecord(csvRecord []string, euCSLRecord *CSLRecord) {
 		}
 	}
 
+	// keep each address row together so the fields stay paired
+	addr := Address{
+		City:               csvRecord[AddressCityIdx],
+		Street:             csvRecord[AddressStreetIdx],
+		PoBox:              csvRecord[AddressPoBoxIdx],
+		ZipCode:            csvRecord[AddressZipCodeIdx],
+		CountryIso2code:    csvRecord[AddressCountryIso2CodeIdx],
+		CountryDescription: csvRecord[AddressCountryDescriptionIdx],
+	}
+	if addr != (Address{}) && !slices.Contains(euCSLRecord.Addresses, addr) {
+		euCSLRecord.Addresses = append(euCSLRecord.Addresses, addr)
+	}
+
 	// birthdate
 	if csvRecord[BirthDateIdx] != "" {
 		if !arrayContains(euCSLRecord.BirthDates, csvRecord[BirthDateIdx]) {
@@ -152,6 +191,20 @@ func unmarshalRecord(csvRecord []string, euCSLRecord *CSLRecord) {
 		euCSLRecord.ValidFromTo = make(map[string]string)
 		euCSLRecord.ValidFromTo[csvRecord[IdentificationValidFromIdx]] = csvRecord[IdentificationValidToIdx]
 	}
+	if len(csvRecord) > IdentificationCountryDescriptionIdx && csvRecord[IdentificationNumberIdx] != "" {
+		ident := Identification{
+			Number:             csvRecord[IdentificationNumberIdx],
+			ValidFrom:          csvRecord[IdentificationValidFromIdx],
+			ValidTo:            csvRecord[IdentificationValidToIdx],
+			TypeCode:           csvRecord[IdentificationTypeCodeIdx],
+			TypeDescription:    csvRecord[IdentificationTypeDescriptionIdx],
+			CountryIso2code:    csvRecord[IdentificationCountryIso2CodeIdx],
+			CountryDescription: csvRecord[IdentificationCountryDescriptionIdx],
+		}
+		if !slices.Contains(euCSLRecord.Identifications, ident) {
+			euCSLRecord.Identifications = append(euCSLRecord.Identifications, ident)
+		}
+	}
 }
 
 func arrayContains(checkArray []string, nameToCheck string) bool {
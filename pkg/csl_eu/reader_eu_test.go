@@ -5,11 +5,14 @@
 package csl_eu
 
 import (
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReadEU(t *testing.T) {
@@ -72,3 +75,25 @@ func TestReadEU(t *testing.T) {
 	assert.Equal(t, expectedBirthCity, euCSLMap[testLogicalID].BirthCities[0])
 	assert.Equal(t, expectedBirthCountryDescription, euCSLMap[testLogicalID].BirthCountries[0])
 }
+
+func TestParseEUWithSkipped(t *testing.T) {
+	row := func(logicalID, name string) string {
+		fields := make([]string, IdentificationCountryDescriptionIdx+1)
+		fields[EntityLogicalIdx] = logicalID
+		fields[NameAliasWholeNameIdx] = name
+		return strings.Join(fields, ";")
+	}
+	header := strings.Repeat("column;", IdentificationCountryDescriptionIdx) + "column"
+
+	lines := []string{
+		header,
+		row("13", "Saddam Hussein Al-Tikriti"),
+		row("13", "Abu Ali"),
+		"20;Malformed row;with too few columns",
+		row("14", "Qusay Saddam Hussein Al-Tikriti"),
+	}
+	records, skipped, err := ParseEUWithSkipped(io.NopCloser(strings.NewReader(strings.Join(lines, "\n"))))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, 1, skipped)
+}
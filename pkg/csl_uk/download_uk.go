@@ -24,8 +24,9 @@ var (
 	ukCSLDownloadURL     = strx.Or(os.Getenv("UK_CSL_DOWNLOAD_URL"), publicCSLDownloadURL)
 )
 
-func DownloadCSL(ctx context.Context, logger log.Logger, initialDir string) (map[string]io.ReadCloser, error) {
+func DownloadCSL(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	ukCSLNameAndSource := make(map[string]string)
 	ukCSLNameAndSource["ConList.csv"] = ukCSLDownloadURL
@@ -33,12 +34,13 @@ func DownloadCSL(ctx context.Context, logger log.Logger, initialDir string) (map
 	return dl.GetFiles(ctx, initialDir, ukCSLNameAndSource)
 }
 
-func DownloadSanctionsList(ctx context.Context, logger log.Logger, initialDir string) (map[string]io.ReadCloser, error) {
+func DownloadSanctionsList(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	ukSanctionsNameAndSource := make(map[string]string)
 
-	latestURL, err := fetchLatestUKSanctionsListURL(ctx, logger, initialDir)
+	latestURL, err := fetchLatestUKSanctionsListURL(ctx, logger, initialDir, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +55,7 @@ var (
 	defaultUKSanctionsListHTML = strx.Or(os.Getenv("UK_CSL_HTML_INDEX_URL"), "https://www.gov.uk/government/publications/the-uk-sanctions-list")
 )
 
-func fetchLatestUKSanctionsListURL(ctx context.Context, logger log.Logger, initialDir string) (string, error) {
+func fetchLatestUKSanctionsListURL(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (string, error) {
 	fromEnv := strings.TrimSpace(os.Getenv("UK_SANCTIONS_LIST_URL"))
 	if fromEnv != "" {
 		return fromEnv, nil
@@ -64,6 +66,7 @@ func fetchLatestUKSanctionsListURL(ctx context.Context, logger log.Logger, initi
 	ukSanctionsNameAndSource["UK_Sanctions_List.ods"] = defaultUKSanctionsListHTML
 
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	pages, err := dl.GetFiles(ctx, initialDir, ukSanctionsNameAndSource)
 	if err != nil {
@@ -13,6 +13,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+
 	"github.com/moov-io/base/log"
 	"github.com/stretchr/testify/require"
 )
@@ -22,7 +24,7 @@ func TestCSLDownload(t *testing.T) {
 		return
 	}
 
-	file, err := DownloadCSL(context.Background(), log.NewNopLogger(), "")
+	file, err := DownloadCSL(context.Background(), log.NewNopLogger(), "", download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -55,7 +57,7 @@ func TestCSLDownload_initialDir(t *testing.T) {
 	// create each file
 	mk(t, "ConList.csv", "file=ConList.csv")
 
-	file, err := DownloadCSL(context.Background(), log.NewNopLogger(), dir)
+	file, err := DownloadCSL(context.Background(), log.NewNopLogger(), dir, download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -84,7 +86,7 @@ func TestUKSanctionsListIndex(t *testing.T) {
 	}
 
 	logger := log.NewTestLogger()
-	foundURL, err := fetchLatestUKSanctionsListURL(context.Background(), logger, "")
+	foundURL, err := fetchLatestUKSanctionsListURL(context.Background(), logger, "", download.Options{})
 	require.NoError(t, err)
 
 	require.Contains(t, foundURL, "UK_Sanctions_List.ods")
@@ -96,7 +98,7 @@ func TestUKSanctionsListDownload(t *testing.T) {
 	}
 
 	logger := log.NewTestLogger()
-	file, err := DownloadSanctionsList(context.Background(), logger, "")
+	file, err := DownloadSanctionsList(context.Background(), logger, "", download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -129,7 +131,7 @@ func TestUKSanctionsListDownload_initialDir(t *testing.T) {
 	// create each file
 	mk(t, "UK_Sanctions_List.ods", "file=UK_Sanctions_List.ods")
 
-	file, err := DownloadSanctionsList(context.Background(), log.NewNopLogger(), dir)
+	file, err := DownloadSanctionsList(context.Background(), log.NewNopLogger(), dir, download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,228 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package csl_uk
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/address"
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// ConvertCSLData maps each UK Consolidated List record into a search.Entity
+func ConvertCSLData(records []CSLRecord) []search.Entity[search.Value] {
+	out := make([]search.Entity[search.Value], 0, len(records))
+	for _, record := range records {
+		out = append(out, CSLToEntity(record))
+	}
+	return out
+}
+
+func CSLToEntity(record CSLRecord) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Source:     search.SourceUKCSL,
+		SourceID:   strconv.Itoa(record.GroupID),
+		SourceData: record,
+	}
+
+	var altNames []string
+	if len(record.Names) > 0 {
+		out.Name = record.Names[0]
+		altNames = record.Names[1:]
+	}
+
+	switch strings.ToLower(strings.TrimSpace(record.GroupType)) {
+	case "individual":
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:          out.Name,
+			AltNames:      altNames,
+			Titles:        record.Titles,
+			Nationalities: splitNumbered(record.Nationalities),
+		}
+		for _, dob := range record.DatesOfBirth {
+			if tt := parseDate(dob); tt != nil {
+				out.Person.BirthDate = tt
+				break
+			}
+		}
+		out.Titles = record.Titles
+
+	case "entity":
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+
+	case "ship":
+		out.Type = search.EntityVessel
+		out.Vessel = &search.Vessel{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+	}
+
+	for _, addr := range record.Addresses {
+		parsed := parseAddress(addr)
+		if parsed.PostalCode == "" && len(record.PostalCodes) == 1 {
+			parsed.PostalCode = record.PostalCodes[0]
+		}
+		if parsed.Country == "" && len(record.Countries) == 1 {
+			parsed.Country = record.Countries[0]
+		}
+		out.Addresses = append(out.Addresses, parsed)
+	}
+
+	out.HistoricalInfo = append(out.HistoricalInfo, makeHistoricalInfo("Listed", record.ListedDates)...)
+	out.HistoricalInfo = append(out.HistoricalInfo, makeHistoricalInfo("UK Sanctions List Date", record.SanctionListDates)...)
+	out.HistoricalInfo = append(out.HistoricalInfo, makeHistoricalInfo("Last Updated", record.LastUpdates)...)
+
+	if len(record.OtherInfos) > 0 {
+		out.SanctionsInfo = &search.SanctionsInfo{
+			Description: strings.Join(record.OtherInfos, " "),
+		}
+	}
+
+	return out
+}
+
+// ConvertSanctionsListData maps each UK Sanctions List record into a search.Entity
+func ConvertSanctionsListData(records []SanctionsListRecord) []search.Entity[search.Value] {
+	out := make([]search.Entity[search.Value], 0, len(records))
+	for _, record := range records {
+		out = append(out, SanctionsListToEntity(record))
+	}
+	return out
+}
+
+func SanctionsListToEntity(record SanctionsListRecord) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Source:     search.SourceUKSanctionsList,
+		SourceID:   record.UniqueID,
+		SourceData: record,
+	}
+
+	var altNames []string
+	if len(record.Names) > 0 {
+		out.Name = record.Names[0]
+		altNames = record.Names[1:]
+	}
+	altNames = append(altNames, record.NonLatinScriptNames...)
+
+	var entityType SLEntityType
+	if record.EntityType != nil {
+		entityType = *record.EntityType
+	}
+	switch entityType {
+	case UKSLIndividual:
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+		if title := strings.TrimSpace(record.NameTitle); title != "" {
+			out.Person.Titles = []string{title}
+			out.Titles = out.Person.Titles
+		}
+
+	case UKSLEntity:
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+
+	case UKSLShip:
+		out.Type = search.EntityVessel
+		out.Vessel = &search.Vessel{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+	}
+
+	for _, addr := range record.Addresses {
+		parsed := parseAddress(addr)
+		if parsed.Country == "" && len(record.AddressCountries) == 1 {
+			parsed.Country = record.AddressCountries[0]
+		}
+		out.Addresses = append(out.Addresses, parsed)
+	}
+
+	if record.CountryOfBirth != "" {
+		out.HistoricalInfo = append(out.HistoricalInfo, makeHistoricalInfo("Country of Birth", []string{record.CountryOfBirth})...)
+	}
+	if record.LastUpdated != "" {
+		out.HistoricalInfo = append(out.HistoricalInfo, makeHistoricalInfo("Last Updated", []string{record.LastUpdated})...)
+	}
+
+	return out
+}
+
+var (
+	numberedValues = regexp.MustCompile(`\(\d+\)`)
+)
+
+// splitNumbered separates values which are written together as "(1) Afghanistan (2) Pakistan"
+func splitNumbered(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range numberedValues.Split(value, -1) {
+			if part = strings.TrimSpace(part); part != "" && !slices.Contains(out, part) {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// parseAddress runs the input through address parsing and falls back to
+// keeping the raw address as Line1 when nothing could be parsed.
+func parseAddress(input string) search.Address {
+	addr := address.ParseAddress(input)
+	if addr == (search.Address{}) {
+		addr.Line1 = strings.TrimSpace(input)
+	}
+	return addr
+}
+
+func makeHistoricalInfo(infoType string, values []string) []search.HistoricalInfo {
+	var out []search.HistoricalInfo
+	for _, value := range values {
+		info := search.HistoricalInfo{
+			Type:  infoType,
+			Value: value,
+		}
+		if tt := parseDate(value); tt != nil {
+			info.Date = *tt
+		}
+		out = append(out, info)
+	}
+	return out
+}
+
+// parseDate reads UK formatted dates (dd/mm/yyyy). Unknown days and months
+// are written as "00", which we treat as the first of the month / year.
+func parseDate(value string) *time.Time {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) != 3 {
+		return nil
+	}
+	if parts[0] == "00" {
+		parts[0] = "01"
+	}
+	if parts[1] == "00" {
+		parts[1] = "01"
+	}
+	tt, err := time.Parse("02/01/2006", strings.Join(parts, "/"))
+	if err != nil {
+		return nil
+	}
+	return &tt
+}
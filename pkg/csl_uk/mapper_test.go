@@ -0,0 +1,133 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package csl_uk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSLToEntity(t *testing.T) {
+	t.Run("individual", func(t *testing.T) {
+		record := CSLRecord{
+			Names:         []string{"HAJI KHAIRULLAH", "HAJI KHAIRULLAH SATTAR"},
+			Titles:        []string{"Haji"},
+			DatesOfBirth:  []string{"00/00/1965"},
+			Nationalities: []string{"Afghanistan"},
+			Addresses:     []string{"Chaman, Baluchistan"},
+			Countries:     []string{"Pakistan"},
+			GroupType:     "Individual",
+			ListedDates:   []string{"02/12/2011"},
+			LastUpdates:   []string{"13/05/2022"},
+			GroupID:       12703,
+		}
+
+		entity := CSLToEntity(record)
+		require.Equal(t, "HAJI KHAIRULLAH", entity.Name)
+		require.Equal(t, search.EntityPerson, entity.Type)
+		require.Equal(t, search.SourceUKCSL, entity.Source)
+		require.Equal(t, "12703", entity.SourceID)
+
+		require.NotNil(t, entity.Person)
+		require.Equal(t, []string{"HAJI KHAIRULLAH SATTAR"}, entity.Person.AltNames)
+		require.Equal(t, []string{"Haji"}, entity.Person.Titles)
+		require.Equal(t, []string{"Afghanistan"}, entity.Person.Nationalities)
+		require.Equal(t, "1965-01-01", entity.Person.BirthDate.Format(time.DateOnly))
+
+		require.Len(t, entity.Addresses, 1)
+		require.Equal(t, "Chaman, Baluchistan", entity.Addresses[0].Line1)
+		require.Equal(t, "Pakistan", entity.Addresses[0].Country)
+
+		require.Len(t, entity.HistoricalInfo, 2)
+		require.Equal(t, "Listed", entity.HistoricalInfo[0].Type)
+		require.Equal(t, "2011-12-02", entity.HistoricalInfo[0].Date.Format(time.DateOnly))
+		require.Equal(t, "Last Updated", entity.HistoricalInfo[1].Type)
+	})
+
+	t.Run("nationalities", func(t *testing.T) {
+		entity := CSLToEntity(CSLRecord{
+			Names:         []string{"Ahmad Shah"},
+			Nationalities: []string{"(1) Afghanistan (2) Pakistan", "Pakistan"},
+			GroupType:     "Individual",
+		})
+		require.Equal(t, []string{"Afghanistan", "Pakistan"}, entity.Person.Nationalities)
+	})
+
+	t.Run("entity", func(t *testing.T) {
+		entity := CSLToEntity(CSLRecord{
+			Names:     []string{"(GENERAL) ORGANIZATION FOR ENGINEERING INDUSTRIES"},
+			GroupType: "Entity",
+			GroupID:   12431,
+		})
+		require.Equal(t, search.EntityBusiness, entity.Type)
+		require.NotNil(t, entity.Business)
+		require.Equal(t, "(GENERAL) ORGANIZATION FOR ENGINEERING INDUSTRIES", entity.Business.Name)
+	})
+
+	t.Run("ship", func(t *testing.T) {
+		entity := CSLToEntity(CSLRecord{
+			Names:     []string{"GLORY"},
+			GroupType: "Ship",
+			GroupID:   15000,
+		})
+		require.Equal(t, search.EntityVessel, entity.Type)
+		require.NotNil(t, entity.Vessel)
+		require.Equal(t, "GLORY", entity.Vessel.Name)
+	})
+}
+
+func TestSanctionsListToEntity(t *testing.T) {
+	t.Run("individual", func(t *testing.T) {
+		entityType := UKSLIndividual
+		entity := SanctionsListToEntity(SanctionsListRecord{
+			LastUpdated:    "13/05/2022",
+			UniqueID:       "AFG0002",
+			Names:          []string{"Abdul Baqi BASIR AWAL SHAH"},
+			NameTitle:      "Maulavi",
+			EntityType:     &entityType,
+			CountryOfBirth: "Afghanistan",
+		})
+		require.Equal(t, search.EntityPerson, entity.Type)
+		require.NotNil(t, entity.Person)
+		require.Equal(t, []string{"Maulavi"}, entity.Person.Titles)
+		require.Equal(t, []string{"Maulavi"}, entity.Titles)
+
+		require.Len(t, entity.HistoricalInfo, 2)
+		require.Equal(t, "Country of Birth", entity.HistoricalInfo[0].Type)
+		require.Equal(t, "Afghanistan", entity.HistoricalInfo[0].Value)
+		require.Equal(t, "Last Updated", entity.HistoricalInfo[1].Type)
+	})
+
+	entityType := UKSLEntity
+	record := SanctionsListRecord{
+		LastUpdated:         "12/01/2022",
+		UniqueID:            "AFG0001",
+		OFSIGroupID:         "12703",
+		Names:               []string{"HAJI KHAIRULLAH HAJI SATTAR MONEY EXCHANGE", "HAJI KHAIRULLAH MONEY EXCHANGE"},
+		NonLatinScriptNames: []string{"حاجی خيرالله و حاجی ستار صرافی"},
+		EntityType:          &entityType,
+		Addresses:           []string{"Branch Number 12, Peshawar, Khyber Paktunkhwa Province, Pakistan"},
+		AddressCountries:    []string{"Pakistan"},
+	}
+
+	entity := SanctionsListToEntity(record)
+	require.Equal(t, "HAJI KHAIRULLAH HAJI SATTAR MONEY EXCHANGE", entity.Name)
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Equal(t, search.SourceUKSanctionsList, entity.Source)
+	require.Equal(t, "AFG0001", entity.SourceID)
+
+	require.NotNil(t, entity.Business)
+	require.Len(t, entity.Business.AltNames, 2)
+
+	require.Len(t, entity.Addresses, 1)
+	require.Equal(t, "Pakistan", entity.Addresses[0].Country)
+
+	require.Len(t, entity.HistoricalInfo, 1)
+	require.Equal(t, "2022-01-12", entity.HistoricalInfo[0].Date.Format(time.DateOnly))
+}
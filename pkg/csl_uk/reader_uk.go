@@ -29,7 +29,23 @@ func ReadCSLFile(fd io.ReadCloser) ([]CSLRecord, CSL, error) {
 	return rows, rowsMap, nil
 }
 
+// ReadCSLFileWithSkipped is ReadCSLFile which also returns how many malformed rows were skipped
+func ReadCSLFileWithSkipped(fd io.ReadCloser) ([]CSLRecord, int, error) {
+	if fd == nil {
+		return nil, 0, errors.New("uk CSL file is empty or missing")
+	}
+	defer fd.Close()
+
+	rows, _, skipped, err := parseCSL(fd)
+	return rows, skipped, err
+}
+
 func ParseCSL(r io.Reader) ([]CSLRecord, CSL, error) {
+	rows, rowsMap, _, err := parseCSL(r)
+	return rows, rowsMap, err
+}
+
+func parseCSL(r io.Reader) ([]CSLRecord, CSL, int, error) {
 	reader := csv.NewReader(r)
 	reader.FieldsPerRecord = 36
 
@@ -39,6 +55,7 @@ func ParseCSL(r io.Reader) ([]CSLRecord, CSL, error) {
 		reader.Read()
 	}
 
+	var skipped int
 	for {
 		record, err := reader.Read()
 		if err != nil {
@@ -50,9 +67,10 @@ func ParseCSL(r io.Reader) ([]CSLRecord, CSL, error) {
 			if errors.Is(err, csv.ErrFieldCount) ||
 				errors.Is(err, csv.ErrBareQuote) ||
 				errors.Is(err, csv.ErrQuote) {
+				skipped++
 				continue
 			}
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
 		if len(record) <= 1 {
@@ -63,7 +81,7 @@ func ParseCSL(r io.Reader) ([]CSLRecord, CSL, error) {
 		// for each record we need to add that to the map
 		groupID, err := strconv.Atoi(record[GroupdIdx])
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
 		// check if entry does not exist
@@ -83,7 +101,7 @@ func ParseCSL(r io.Reader) ([]CSLRecord, CSL, error) {
 	for _, row := range report {
 		totalReport = append(totalReport, *row)
 	}
-	return totalReport, report, nil
+	return totalReport, report, skipped, nil
 }
 
 func unmarshalCSLRecord(csvRecord []string, ukCSLRecord *CSLRecord) {
@@ -200,40 +218,48 @@ func unmarshalCSLRecord(csvRecord []string, ukCSLRecord *CSLRecord) {
 }
 
 func ReadSanctionsListFile(f io.ReadCloser) ([]SanctionsListRecord, SanctionsListMap, error) {
+	rows, rowsMap, _, err := readSanctionsListFile(f)
+	return rows, rowsMap, err
+}
+
+// ReadSanctionsListFileWithSkipped is ReadSanctionsListFile which also returns how many rows were
+// skipped for missing columns
+func ReadSanctionsListFileWithSkipped(f io.ReadCloser) ([]SanctionsListRecord, int, error) {
+	rows, _, skipped, err := readSanctionsListFile(f)
+	return rows, skipped, err
+}
+
+func readSanctionsListFile(f io.ReadCloser) ([]SanctionsListRecord, SanctionsListMap, int, error) {
 	if f == nil {
-		return nil, nil, errors.New("uk sanctions list file is empty or missing")
+		return nil, nil, 0, errors.New("uk sanctions list file is empty or missing")
 	}
 	defer f.Close()
 	content, err := io.ReadAll(f)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	fd, err := ods.NewReader(bytes.NewReader(content), int64(len(content)))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	defer fd.Close()
 
 	doc := new(ods.Doc)
 	err = fd.ParseContent(doc)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
-	rows, rowsMap, err := parseSanctionsList(doc)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return rows, rowsMap, nil
+	return parseSanctionsList(doc)
 }
 
-func parseSanctionsList(doc *ods.Doc) ([]SanctionsListRecord, SanctionsListMap, error) {
+func parseSanctionsList(doc *ods.Doc) ([]SanctionsListRecord, SanctionsListMap, int, error) {
 	// read from the ods document
 	var totalReport []SanctionsListRecord
 	report := SanctionsListMap{}
 
 	// unmarshal each row into a uk sanctions list record
+	var skipped int
 	if len(doc.Table) > 0 {
 		for i, record := range doc.Table[0].Row {
 
@@ -242,6 +268,12 @@ func parseSanctionsList(doc *ods.Doc) ([]SanctionsListRecord, SanctionsListMap,
 				continue
 			}
 
+			// rows without every column can't be read
+			if len(record.Cell) < UKSL_CountryOfBirthIdx {
+				skipped++
+				continue
+			}
+
 			// need a length of row check since we are using the string representation
 			uniqueIDCell := record.Cell[UKSL_UniqueIDIdx]
 			b := new(bytes.Buffer)
@@ -262,7 +294,7 @@ func parseSanctionsList(doc *ods.Doc) ([]SanctionsListRecord, SanctionsListMap,
 	for _, row := range report {
 		totalReport = append(totalReport, *row)
 	}
-	return totalReport, report, nil
+	return totalReport, report, skipped, nil
 }
 
 func unmarshalSanctionsListRecord(record []ods.Cell, ukSLRecord *SanctionsListRecord) {
@@ -308,8 +340,8 @@ func unmarshalSanctionsListRecord(record []ods.Cell, ukSLRecord *SanctionsListRe
 		ukSLRecord.Names = append(ukSLRecord.Names, name)
 	}
 
-	if !record[UKSL_NameTypeIdx].IsEmpty() && ukSLRecord.NameTitle == "" {
-		ukSLRecord.NameTitle = record[UKSL_NameTypeIdx].PlainText(b)
+	if !record[UKSL_TitleIdx].IsEmpty() && ukSLRecord.NameTitle == "" {
+		ukSLRecord.NameTitle = record[UKSL_TitleIdx].PlainText(b)
 	}
 
 	if !record[UKSL_NonLatinScriptIdx].IsEmpty() && !arrayContains(ukSLRecord.NonLatinScriptNames, record[UKSL_NonLatinScriptIdx].PlainText(b)) {
@@ -374,7 +406,7 @@ func unmarshalSanctionsListRecord(record []ods.Cell, ukSLRecord *SanctionsListRe
 
 	cob := record[UKSL_CountryOfBirthIdx]
 	cobValue := record[UKSL_CountryOfBirthIdx].PlainText(b)
-	if !cob.IsEmpty() && ukSLRecord.CountryOfBirth != "" {
+	if !cob.IsEmpty() && ukSLRecord.CountryOfBirth == "" {
 		ukSLRecord.CountryOfBirth = cobValue
 	}
 }
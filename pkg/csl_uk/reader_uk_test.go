@@ -100,7 +100,7 @@ func TestReadUKSanctionsList(t *testing.T) {
 		assert.Equal(t, "TAe.010", record.UNReferenceNumber)
 		assert.Equal(t, "HAJI KHAIRULLAH HAJI SATTAR MONEY EXCHANGE", record.Names[0])
 		assert.Len(t, record.Names, 9)
-		assert.Equal(t, "Primary Name", record.NameTitle)
+		assert.Empty(t, record.NameTitle)
 		assert.NotEmpty(t, record.NonLatinScriptNames)
 		assert.Equal(t, UKSLEntity, *record.EntityType)
 		assert.NotEmpty(t, record.Addresses)
@@ -17,6 +17,14 @@ type CSL struct {
 	DTCs    []DTC    // ITAR Debarred (DTC) - State Department
 	CMICs   []CMIC   // Non-SDN Chinese Military-Industrial Complex Companies List (CMIC) - Treasury Department
 	NS_MBSs []NS_MBS // Non-SDN Menu-Based Sanctions List (NS-MBS List) - Treasury Department
+
+	Skipped int // malformed rows which couldn't be read
+}
+
+// Len returns how many records were read from every list
+func (c CSL) Len() int {
+	return len(c.ELs) + len(c.MEUs) + len(c.SSIs) + len(c.UVLs) + len(c.FSEs) + len(c.ISNs) +
+		len(c.PLCs) + len(c.CAPs) + len(c.DTCs) + len(c.CMICs) + len(c.NS_MBSs)
 }
 
 // This is the order of the columns in the CSL
@@ -54,6 +62,8 @@ const (
 
 // EL is the Entity List (EL) - Bureau of Industry and Security
 type EL struct {
+	// Source is the name of the list within the CSL this record belongs to
+	Source string `json:"source"`
 	// ID is the unique identifier for the entity
 	ID string `json:"id"`
 	// Name is the primary name of the entity
@@ -77,6 +87,7 @@ type EL struct {
 }
 
 type MEU struct {
+	Source    string `json:"source"`
 	EntityID  string `json:"entityID"`
 	Name      string `json:"name"`
 	Addresses string `json:"addresses"`
@@ -87,6 +98,8 @@ type MEU struct {
 
 // SSI is the Sectoral Sanctions Identifications List - Treasury Department
 type SSI struct {
+	// Source is the name of the list within the CSL this record belongs to
+	Source string `json:"source"`
 	// EntityID (ent_num) is the unique record identifier/unique listing identifier
 	EntityID string `json:"entityID"`
 	// Type is the entity type (e.g. individual, vessel, aircraft, etc)
@@ -110,6 +123,7 @@ type SSI struct {
 }
 
 type UVL struct {
+	Source        string   `json:"source"`
 	EntityID      string   `json:"entityID"`
 	Name          string   `json:"name"`
 	Addresses     []string `json:"addresses"`
@@ -118,6 +132,7 @@ type UVL struct {
 }
 
 type ISN struct {
+	Source                string   `json:"source"`
 	EntityID              string   `json:"entityID"`
 	Programs              []string `json:"programs"`
 	Name                  string   `json:"name"`
@@ -130,6 +145,7 @@ type ISN struct {
 }
 
 type FSE struct {
+	Source        string   `json:"source"`
 	EntityID      string   `json:"entityID"`
 	EntityNumber  string   `json:"entityNumber"`
 	Type          string   `json:"type"`
@@ -144,6 +160,7 @@ type FSE struct {
 }
 
 type PLC struct {
+	Source         string   `json:"source"`
 	EntityID       string   `json:"entityID"`
 	EntityNumber   string   `json:"entityNumber"`
 	Type           string   `json:"type"`
@@ -159,6 +176,7 @@ type PLC struct {
 }
 
 type CAP struct {
+	Source         string   `json:"source"`
 	EntityID       string   `json:"entityID"`
 	EntityNumber   string   `json:"entityNumber"`
 	Type           string   `json:"type"`
@@ -173,6 +191,7 @@ type CAP struct {
 }
 
 type DTC struct {
+	Source                string   `json:"source"`
 	EntityID              string   `json:"entityID"`
 	Name                  string   `json:"name"`
 	FederalRegisterNotice string   `json:"federalRegisterNotice"`
@@ -182,6 +201,7 @@ type DTC struct {
 }
 
 type CMIC struct {
+	Source         string   `json:"source"`
 	EntityID       string   `json:"entityID"`
 	EntityNumber   string   `json:"entityNumber"`
 	Type           string   `json:"type"`
@@ -196,6 +216,7 @@ type CMIC struct {
 }
 
 type NS_MBS struct {
+	Source         string   `json:"source"`
 	EntityID       string   `json:"entityID"`
 	EntityNumber   string   `json:"entityNumber"`
 	Type           string   `json:"type"`
@@ -9,6 +9,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+
 	"github.com/moov-io/base/log"
 
 	"github.com/stretchr/testify/require"
@@ -23,7 +25,7 @@ func TestCSL(t *testing.T) {
 	dir, err := os.MkdirTemp("", "csl")
 	require.NoError(t, err)
 
-	file, err := Download(context.Background(), logger, dir)
+	file, err := Download(context.Background(), logger, dir, download.Options{})
 	require.NoError(t, err)
 
 	cslRecords, err := ReadFile(file["csl.csv"])
@@ -21,8 +21,9 @@ var (
 	usDownloadURL       = strx.Or(os.Getenv("CSL_DOWNLOAD_TEMPLATE"), os.Getenv("US_CSL_DOWNLOAD_URL"), publicUSDownloadURL)
 )
 
-func Download(ctx context.Context, logger log.Logger, initialDir string) (map[string]io.ReadCloser, error) {
+func Download(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	cslURL, err := buildDownloadURL(usDownloadURL)
 	if err != nil {
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+
 	"github.com/moov-io/base/log"
 )
 
@@ -20,7 +22,7 @@ func TestDownload(t *testing.T) {
 		return
 	}
 
-	file, err := Download(context.Background(), log.NewNopLogger(), "")
+	file, err := Download(context.Background(), log.NewNopLogger(), "", download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -54,7 +56,7 @@ func TestDownload_initialDir(t *testing.T) {
 	mk(t, "csl.csv", "file=csl.csv")
 	mk(t, "csl.csv", "file=csl.csv")
 
-	file, err := Download(context.Background(), log.NewNopLogger(), dir)
+	file, err := Download(context.Background(), log.NewNopLogger(), dir, download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -5,185 +5,493 @@
 package csl_us
 
 import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/address"
 	"github.com/moov-io/watchman/pkg/search"
 )
 
-// Entity List – Bureau of Industry and Security
-func EL_ToEntity(record EL) search.Entity[EL] {
-	out := search.Entity[EL]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
+// ConvertSanctionsData maps every record from each CSL sub-list into a search.Entity
+//
+// Each entity's SanctionsInfo.List is the sub-list's code (e.g. "EL") and the original
+// record is kept in SourceData, which includes the sub-list's full name.
+func ConvertSanctionsData(data CSL) []search.Entity[search.Value] {
+	var out []search.Entity[search.Value]
+	for _, record := range data.ELs {
+		out = append(out, EL_ToEntity(record))
 	}
+	for _, record := range data.MEUs {
+		out = append(out, MEU_ToEntity(record))
+	}
+	for _, record := range data.SSIs {
+		out = append(out, SSI_ToEntity(record))
+	}
+	for _, record := range data.UVLs {
+		out = append(out, UVL_ToEntity(record))
+	}
+	for _, record := range data.FSEs {
+		out = append(out, FSE_ToEntity(record))
+	}
+	for _, record := range data.ISNs {
+		out = append(out, ISN_ToEntity(record))
+	}
+	for _, record := range data.PLCs {
+		out = append(out, PLC_ToEntity(record))
+	}
+	for _, record := range data.CAPs {
+		out = append(out, CAP_ToEntity(record))
+	}
+	for _, record := range data.DTCs {
+		out = append(out, DTC_ToEntity(record))
+	}
+	for _, record := range data.CMICs {
+		out = append(out, CMIC_ToEntity(record))
+	}
+	for _, record := range data.NS_MBSs {
+		out = append(out, NS_MBS_ToEntity(record))
+	}
+	return out
+}
 
-	out.Name = record.Name
-	// out.Type =  // TODO(adam):
-
-	// record.AlternateNames []string // TODO(adam):
-	// record.Addresses []string  // TODO(adam):
-
+// Entity List – Bureau of Industry and Security
+func EL_ToEntity(record EL) search.Entity[search.Value] {
+	out := newEntity("EL", record.ID, record.Name, "", record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	addDescription(&out, "Federal Register Notice", record.FRNotice)
+	addDescription(&out, "License Requirement", record.LicenseRequirement)
+	addDescription(&out, "License Policy", record.LicensePolicy)
+	addDate(&out, "Start Date", record.StartDate)
 	return out
 }
 
 // Military End User List
-func MEU_ToEntity(record MEU) search.Entity[MEU] {
-	out := search.Entity[MEU]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
-	}
-
-	out.Name = record.Name
-
-	// Name      string `json:"name"`
-	// Addresses string `json:"addresses"`
-
+func MEU_ToEntity(record MEU) search.Entity[search.Value] {
+	out := newEntity("MEU", record.EntityID, record.Name, "", nil, record)
+	out.Addresses = mapAddresses(strings.Split(record.Addresses, ";"))
+	addDescription(&out, "Federal Register Notice", record.FRNotice)
+	addDate(&out, "Start Date", record.StartDate)
+	addDate(&out, "End Date", record.EndDate)
 	return out
 }
 
 // Sectoral Sanctions Identifications List (SSI) - Treasury Department
-func SSI_ToEntity(record SSI) search.Entity[SSI] {
-	out := search.Entity[SSI]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
-	}
-
-	out.Name = record.Name
-
-	// Addresses []string `json:"addresses"`
-	// Remarks []string `json:"remarks"`
-	// AlternateNames []string `json:"alternateNames"`
-
-	// IDsOnRecord []string `json:"ids"`
-
+func SSI_ToEntity(record SSI) search.Entity[search.Value] {
+	out := newEntity("SSI", record.EntityID, record.Name, record.Type, record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapIDs(&out, record.IDsOnRecord)
+	mapRemarks(&out, record.Programs, record.Remarks)
 	return out
 }
 
 // Unverified List – Bureau of Industry and Security
-func UVL_ToEntity(record UVL) search.Entity[UVL] {
-	out := search.Entity[UVL]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
-	}
-
-	out.Name = record.Name
-	// Addresses     []string `json:"addresses"`
-
+func UVL_ToEntity(record UVL) search.Entity[search.Value] {
+	out := newEntity("UVL", record.EntityID, record.Name, "", nil, record)
+	out.Addresses = mapAddresses(record.Addresses)
 	return out
 }
 
 // Foreign Sanctions Evaders (FSE) - Treasury Department
-func FSE_ToEntity(record FSE) search.Entity[FSE] {
-	out := search.Entity[FSE]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
-	}
-
-	out.Name = record.Name
-
-	// Type          string   `json:"type"`
-	// Addresses     []string `json:"addresses,omitempty"`
-	// DatesOfBirth  string   `json:"datesOfBirth"`
-	// IDs           []string `json:"IDs"`
-
+func FSE_ToEntity(record FSE) search.Entity[search.Value] {
+	out := newEntity("FSE", record.EntityID, record.Name, record.Type, nil, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapBirthDate(&out, record.DatesOfBirth)
+	mapNationalities(&out, record.Citizenships)
+	mapIDs(&out, record.IDs)
+	mapRemarks(&out, record.Programs, nil)
 	return out
 }
 
 // Nonproliferation Sanctions (ISN) - State Department
-func ISN_ToEntity(record ISN) search.Entity[ISN] {
-	out := search.Entity[ISN]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
-	}
-
-	out.Name = record.Name
-
-	// Remarks               []string `json:"remarks,omitempty"`
-	// AlternateNames        []string `json:"alternateNames,omitempty"`
-
+func ISN_ToEntity(record ISN) search.Entity[search.Value] {
+	out := newEntity("ISN", record.EntityID, record.Name, "", record.AlternateNames, record)
+	mapRemarks(&out, record.Programs, record.Remarks)
+	addDescription(&out, "Federal Register Notice", record.FederalRegisterNotice)
+	addDate(&out, "Start Date", record.StartDate)
 	return out
 }
 
 // Palestinian Legislative Council List (PLC) - Treasury Department
-func PLC_ToEntity(record PLC) search.Entity[PLC] {
-	out := search.Entity[PLC]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
+func PLC_ToEntity(record PLC) search.Entity[search.Value] {
+	out := newEntity("PLC", record.EntityID, record.Name, record.Type, record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapBirthDate(&out, record.DatesOfBirth)
+
+	var remarks []string
+	if record.Remarks != "" {
+		remarks = append(remarks, record.Remarks)
 	}
+	mapRemarks(&out, record.Programs, remarks)
+	return out
+}
+
+// CAPTA (formerly Foreign Financial Institutions Subject to Part 561 - Treasury Department)
+func CAP_ToEntity(record CAP) search.Entity[search.Value] {
+	out := newEntity("CAP", record.EntityID, record.Name, record.Type, record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapIDs(&out, record.IDs)
+	mapRemarks(&out, record.Programs, record.Remarks)
+	return out
+}
 
-	out.Name = record.Name
+// ITAR Debarred (DTC) - State Department
+func DTC_ToEntity(record DTC) search.Entity[search.Value] {
+	out := newEntity("DTC", record.EntityID, record.Name, "", record.AlternateNames, record)
+	addDescription(&out, "Federal Register Notice", record.FederalRegisterNotice)
+	return out
+}
 
-	// Type          string   `json:"type"`
-	// Addresses     []string `json:"addresses,omitempty"`
-	// DatesOfBirth  string   `json:"datesOfBirth"`
-	// IDs           []string `json:"IDs"`
-	// Remarks               []string `json:"remarks,omitempty"`
+// Non-SDN Chinese Military-Industrial Complex Companies List (CMIC) - Treasury Department
+func CMIC_ToEntity(record CMIC) search.Entity[search.Value] {
+	out := newEntity("CMIC", record.EntityID, record.Name, record.Type, record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapIDs(&out, record.IDs)
+	mapRemarks(&out, record.Programs, record.Remarks)
+	return out
+}
 
+// Non-SDN Menu-Based Sanctions List (NS-MBS List) - Treasury Department
+func NS_MBS_ToEntity(record NS_MBS) search.Entity[search.Value] {
+	out := newEntity("NS-MBS", record.EntityID, record.Name, record.Type, record.AlternateNames, record)
+	out.Addresses = mapAddresses(record.Addresses)
+	mapIDs(&out, record.IDs)
+	mapRemarks(&out, record.Programs, record.Remarks)
 	return out
 }
 
-// CAPTA (formerly Foreign Financial Institutions Subject to Part 561 - Treasury Department)
-func CAP_ToEntity(record CAP) search.Entity[CAP] {
-	out := search.Entity[CAP]{
-		Source:     search.SourceUSCSL,
+// newEntity creates the entity and its type specific struct. Sub-lists which do not publish
+// a type (EL, MEU, UVL, ISN and DTC) have it guessed from the name.
+func newEntity(list, sourceID, name, entityType string, altNames []string, record search.Value) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Name:     strings.TrimSpace(name),
+		Source:   search.SourceUSCSL,
+		SourceID: sourceID,
+		SanctionsInfo: &search.SanctionsInfo{
+			List: list,
+		},
 		SourceData: record,
 	}
 
-	out.Name = record.Name
+	entityType = strings.ToLower(strings.TrimSpace(entityType))
+	if entityType == "" && !isBusiness(out.Name) {
+		entityType = "individual"
+	}
 
-	// Type          string   `json:"type"`
-	// Addresses     []string `json:"addresses,omitempty"`
-	// DatesOfBirth  string   `json:"datesOfBirth"`
-	// IDs           []string `json:"IDs"`
-	// Remarks               []string `json:"remarks,omitempty"`
+	switch entityType {
+	case "individual":
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+
+	case "vessel":
+		out.Type = search.EntityVessel
+		out.Vessel = &search.Vessel{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+
+	case "aircraft":
+		out.Type = search.EntityAircraft
+		out.Aircraft = &search.Aircraft{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+
+	default:
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+	}
 
 	return out
 }
 
-// ITAR Debarred (DTC) - State Department
-func DTC_ToEntity(record DTC) search.Entity[DTC] {
-	out := search.Entity[DTC]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
+var businessWords = []string{
+	"ACADEMY", "AG", "AIRLINES", "AO", "ASSOCIATION", "B.V.", "BANK", "BUREAU", "BV", "CENTER", "CENTRE",
+	"CO", "CO.", "COMPANY", "CORP", "CORP.", "CORPORATION", "ELECTRONICS", "ENGINEERING", "ENTERPRISE",
+	"ENTERPRISES", "FACTORY", "FZE", "FZCO", "GMBH", "GROUP", "INC", "INC.", "INDUSTRIES", "INSTITUTE",
+	"INTERNATIONAL", "JSC", "LABORATORY", "LIMITED", "LLC", "L.L.C.", "LTD", "LTD.", "OAO", "OOO", "PJSC",
+	"PLANT", "S.A.", "SA", "SERVICES", "SOLUTIONS", "SRL", "SYSTEMS", "TECHNOLOGIES", "TECHNOLOGY",
+	"TRADING", "UNIVERSITY",
+}
+
+// isBusiness guesses if a name belongs to a business for sub-lists which don't publish the party's type.
+// Single word names, names containing digits and names with a business word (e.g. "LTD") are businesses.
+func isBusiness(name string) bool {
+	words := strings.Fields(strings.ToUpper(name))
+	if len(words) < 2 {
+		return true
 	}
+	for _, word := range words {
+		word = strings.Trim(word, ",()")
+		if strings.ContainsAny(word, "0123456789") {
+			return true
+		}
+		for _, w := range businessWords {
+			if word == w {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	out.Name = record.Name
+// mapAddresses reads CSL addresses, which are formatted as "street, city, postal code, country code".
+// When address parsing finds nothing the country code is split off and the rest is kept as Line1.
+func mapAddresses(inputs []string) []search.Address {
+	var out []search.Address
+	for _, input := range inputs {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+
+		addr := address.ParseAddress(input)
+		if addr == (search.Address{}) {
+			parts := strings.Split(input, ",")
+			if len(parts) > 1 {
+				if country := strings.TrimSpace(parts[len(parts)-1]); len(country) == 2 {
+					addr.Country = country
+					parts = parts[:len(parts)-1]
+				}
+			}
+			addr.Line1 = strings.TrimSpace(strings.Join(parts, ","))
+		}
+		out = append(out, addr)
+	}
+	return out
+}
 
-	// AlternateNames        []string `json:"alternateNames,omitempty"`
+var (
+	dateFormats = []string{"2006-01-02", "2006-01", "2006", "02 Jan 2006"}
+)
 
-	return out
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, format := range dateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return &tt
+		}
+	}
+	return nil
 }
 
-// Non-SDN Chinese Military-Industrial Complex Companies List (CMIC) - Treasury Department
-func CMIC_ToEntity(record CMIC) search.Entity[CMIC] {
-	out := search.Entity[CMIC]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
+func mapBirthDate(out *search.Entity[search.Value], datesOfBirth string) {
+	if out.Person == nil {
+		return
 	}
+	for _, dob := range strings.Split(datesOfBirth, ";") {
+		if tt := parseDate(dob); tt != nil {
+			out.Person.BirthDate = tt
+			return
+		}
+	}
+}
 
-	out.Name = record.Name
+// mapNationalities reads citizenships, which are separated by semicolons
+func mapNationalities(out *search.Entity[search.Value], citizenships string) {
+	if out.Person == nil {
+		return
+	}
+	for _, country := range strings.Split(citizenships, ";") {
+		if country = strings.TrimSpace(country); country != "" {
+			out.Person.Nationalities = append(out.Person.Nationalities, country)
+		}
+	}
+}
 
-	// Type          string   `json:"type"`
-	// Addresses     []string `json:"addresses,omitempty"`
-	// DatesOfBirth  string   `json:"datesOfBirth"`
-	// IDs           []string `json:"IDs"`
-	// Remarks               []string `json:"remarks,omitempty"`
+// addDescription appends a labeled detail of the listing, such as its Federal Register notice, to SanctionsInfo
+func addDescription(out *search.Entity[search.Value], label, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if out.SanctionsInfo == nil {
+		out.SanctionsInfo = &search.SanctionsInfo{}
+	}
+	detail := label + ": " + value
+	if out.SanctionsInfo.Description != "" {
+		detail = out.SanctionsInfo.Description + "; " + detail
+	}
+	out.SanctionsInfo.Description = detail
+}
 
-	return out
+// addDate records when a listing started or ended as HistoricalInfo
+func addDate(out *search.Entity[search.Value], infoType, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	info := search.HistoricalInfo{
+		Type:  infoType,
+		Value: value,
+	}
+	if tt := parseDate(value); tt != nil {
+		info.Date = *tt
+	}
+	out.HistoricalInfo = append(out.HistoricalInfo, info)
 }
 
-// Non-SDN Menu-Based Sanctions List (NS-MBS List) - Treasury Department
-func NS_MBS_ToEntity(record NS_MBS) search.Entity[NS_MBS] {
-	out := search.Entity[NS_MBS]{
-		Source:     search.SourceUSCSL,
-		SourceData: record,
+// mapIDs reads CSL identifiers, which are formatted as "[country, ]value, type".
+//
+// Contact details, gender and dates are mapped onto their own fields, directive notices
+// are dropped and the remaining values become government IDs or identifiers.
+func mapIDs(out *search.Entity[search.Value], ids []string) {
+	for _, id := range ids {
+		parts := strings.Split(id, ",")
+		if len(parts) < 2 {
+			continue
+		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+
+		idType := parts[len(parts)-1]
+		values := parts[:len(parts)-1]
+
+		var country string
+		if len(values) > 1 && len(values[0]) == 2 {
+			country = values[0]
+			values = values[1:]
+		}
+		value := strings.Join(values, ", ")
+
+		lowerType := strings.ToLower(idType)
+		switch {
+		case value == "" || strings.Contains(lowerType, "directive"):
+			continue
+
+		case lowerType == "website":
+			out.Contact.Websites = append(out.Contact.Websites, value)
+
+		case lowerType == "email address":
+			out.Contact.EmailAddresses = append(out.Contact.EmailAddresses, value)
+
+		case lowerType == "phone number" || lowerType == "telephone":
+			out.Contact.PhoneNumbers = append(out.Contact.PhoneNumbers, value)
+
+		case lowerType == "fax":
+			out.Contact.FaxNumbers = append(out.Contact.FaxNumbers, value)
+
+		case lowerType == "gender":
+			if out.Person != nil {
+				out.Person.Gender = mapGender(value)
+			}
+
+		case lowerType == "organization established date":
+			if out.Business != nil {
+				out.Business.Created = parseDate(value)
+			}
+
+		case strings.Contains(lowerType, "date"):
+			info := search.HistoricalInfo{
+				Type:  strings.TrimSuffix(idType, ":"),
+				Value: value,
+			}
+			if tt := parseDate(value); tt != nil {
+				info.Date = *tt
+			}
+			out.HistoricalInfo = append(out.HistoricalInfo, info)
+
+		case out.Person != nil:
+			out.Person.GovernmentIDs = append(out.Person.GovernmentIDs, search.GovernmentID{
+				Type:       mapGovernmentIDType(lowerType),
+				Country:    country,
+				Identifier: value,
+			})
+
+		case out.Vessel != nil && strings.HasPrefix(lowerType, "vessel registration"):
+			out.Vessel.IMONumber = strings.TrimPrefix(value, "IMO ")
+
+		case out.Vessel != nil && lowerType == "mmsi":
+			out.Vessel.MMSI = value
+
+		case out.Aircraft != nil && strings.Contains(lowerType, "serial"):
+			out.Aircraft.SerialNumber = value
+
+		case out.Business != nil:
+			out.Business.Identifier = append(out.Business.Identifier, search.Identifier{
+				Name:       idType,
+				Country:    country,
+				Identifier: value,
+			})
+		}
 	}
+}
 
-	out.Name = record.Name
+func mapGender(value string) search.Gender {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "male":
+		return search.GenderMale
+	case "female":
+		return search.GenderFemale
+	}
+	return search.GenderUnknown
+}
 
-	// Type          string   `json:"type"`
-	// Addresses     []string `json:"addresses,omitempty"`
-	// DatesOfBirth  string   `json:"datesOfBirth"`
-	// IDs           []string `json:"IDs"`
-	// Remarks               []string `json:"remarks,omitempty"`
+func mapGovernmentIDType(idType string) search.GovernmentIDType {
+	switch {
+	case strings.Contains(idType, "diplomatic passport"):
+		return search.GovernmentIDDiplomaticPass
+	case strings.Contains(idType, "passport"):
+		return search.GovernmentIDPassport
+	case strings.Contains(idType, "national id"):
+		return search.GovernmentIDNational
+	case strings.Contains(idType, "tax id"):
+		return search.GovernmentIDTax
+	case strings.Contains(idType, "ssn"):
+		return search.GovernmentIDSSN
+	case strings.Contains(idType, "cedula"):
+		return search.GovernmentIDCedula
+	case strings.Contains(idType, "curp"):
+		return search.GovernmentIDCURP
+	case strings.Contains(idType, "driver"):
+		return search.GovernmentIDDriversLicense
+	case strings.Contains(idType, "birth certificate"):
+		return search.GovernmentIDBirthCert
+	}
+	return search.GovernmentIDPersonalID
+}
 
-	return out
+var (
+	linkedToRegex = regexp.MustCompile(`\(Linked To: ([^)]+)\)`)
+)
+
+// mapRemarks sets the programs and remarks as SanctionsInfo and pulls out "Linked To" affiliations.
+func mapRemarks(out *search.Entity[search.Value], programs []string, remarks []string) {
+	var descriptions []string
+	for _, remark := range remarks {
+		remark = strings.TrimSpace(remark)
+		if remark == "" {
+			continue
+		}
+		if matches := linkedToRegex.FindAllStringSubmatch(remark, -1); matches != nil {
+			for _, m := range matches {
+				out.Affiliations = append(out.Affiliations, search.Affiliation{
+					EntityName: strings.TrimSpace(m[1]),
+					Type:       "Linked To",
+				})
+			}
+			continue
+		}
+		descriptions = append(descriptions, remark)
+	}
+
+	if len(programs) == 0 && len(descriptions) == 0 {
+		return
+	}
+	if out.SanctionsInfo == nil {
+		out.SanctionsInfo = &search.SanctionsInfo{}
+	}
+	out.SanctionsInfo.Programs = programs
+	out.SanctionsInfo.Description = strings.Join(descriptions, " ")
+	if strings.Contains(strings.ToLower(out.SanctionsInfo.Description), "secondary sanctions") {
+		out.SanctionsInfo.Secondary = true
+	}
 }
@@ -0,0 +1,213 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package csl_us
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSE_ToEntity(t *testing.T) {
+	record := FSE{
+		Source:       "Foreign Sanctions Evaders (FSE) - Treasury Department",
+		EntityID:     "17526",
+		Type:         "Individual",
+		Programs:     []string{"SYRIA", "FSE-SY"},
+		Name:         "BEKTAS, Halis",
+		DatesOfBirth: "1966-02-13",
+		Citizenships: "TR; CH",
+		IDs:          []string{"CH, X0906223, Passport", "Male, Gender"},
+	}
+
+	entity := FSE_ToEntity(record)
+	require.Equal(t, "BEKTAS, Halis", entity.Name)
+	require.Equal(t, search.EntityPerson, entity.Type)
+	require.Equal(t, search.SourceUSCSL, entity.Source)
+	require.Equal(t, "17526", entity.SourceID)
+
+	require.NotNil(t, entity.Person)
+	require.Equal(t, search.GenderMale, entity.Person.Gender)
+	require.Equal(t, "1966-02-13", entity.Person.BirthDate.Format(time.DateOnly))
+	require.Equal(t, []string{"TR", "CH"}, entity.Person.Nationalities)
+
+	expected := []search.GovernmentID{
+		{Type: search.GovernmentIDPassport, Country: "CH", Identifier: "X0906223"},
+	}
+	require.Equal(t, expected, entity.Person.GovernmentIDs)
+
+	require.NotNil(t, entity.SanctionsInfo)
+	require.Equal(t, []string{"SYRIA", "FSE-SY"}, entity.SanctionsInfo.Programs)
+
+	fse, ok := entity.SourceData.(FSE)
+	require.True(t, ok)
+	require.Equal(t, record.Source, fse.Source)
+}
+
+func TestCAP_ToEntity(t *testing.T) {
+	record := CAP{
+		Source:         "Capta List (CAP) - Treasury Department",
+		EntityID:       "20002",
+		Type:           "Entity",
+		Programs:       []string{"UKRAINE-EO13662", "RUSSIA-EO14024"},
+		Name:           "BM BANK PUBLIC JOINT STOCK COMPANY",
+		Addresses:      []string{"Bld 3 8/15, Rozhdestvenka St., Moscow, 107996, RU"},
+		Remarks:        []string{"All offices worldwide", "(Linked To: VTB BANK PUBLIC JOINT STOCK COMPANY)"},
+		AlternateNames: []string{"BM BANK JSC", "BANK OF MOSCOW"},
+		IDs: []string{
+			"RU, 1027700159497, Registration Number",
+			"MOSWRUMM, SWIFT/BIC",
+			"www.bm.ru, Website",
+			"Subject to Directive 1, Executive Order 13662 Directive Determination -",
+			"31 Jul 1990, Organization Established Date",
+		},
+	}
+
+	entity := CAP_ToEntity(record)
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Equal(t, "20002", entity.SourceID)
+
+	require.NotNil(t, entity.Business)
+	require.Equal(t, []string{"BM BANK JSC", "BANK OF MOSCOW"}, entity.Business.AltNames)
+	require.Equal(t, "1990-07-31", entity.Business.Created.Format(time.DateOnly))
+
+	expected := []search.Identifier{
+		{Name: "Registration Number", Country: "RU", Identifier: "1027700159497"},
+		{Name: "SWIFT/BIC", Identifier: "MOSWRUMM"},
+	}
+	require.Equal(t, expected, entity.Business.Identifier)
+	require.Equal(t, []string{"www.bm.ru"}, entity.Contact.Websites)
+
+	require.Len(t, entity.Addresses, 1)
+	require.Equal(t, "RU", entity.Addresses[0].Country)
+
+	require.Len(t, entity.Affiliations, 1)
+	require.Equal(t, "VTB BANK PUBLIC JOINT STOCK COMPANY", entity.Affiliations[0].EntityName)
+
+	require.NotNil(t, entity.SanctionsInfo)
+	require.Equal(t, "All offices worldwide", entity.SanctionsInfo.Description)
+}
+
+func TestConvertSanctionsData(t *testing.T) {
+	data := CSL{
+		ELs:  []EL{{ID: "1", Name: "GBNTT"}},
+		DTCs: []DTC{{EntityID: "2", Name: "Yasmin Ahmed"}},
+		SSIs: []SSI{{EntityID: "3", Name: "AK TRANSNEFT OAO", Type: "Entity"}},
+	}
+
+	entities := ConvertSanctionsData(data)
+	require.Len(t, entities, 3)
+	for _, entity := range entities {
+		require.Equal(t, search.SourceUSCSL, entity.Source)
+		require.NotEmpty(t, entity.SourceID)
+	}
+
+	require.Equal(t, search.EntityBusiness, entities[0].Type)
+	require.Equal(t, "EL", entities[0].SanctionsInfo.List)
+
+	require.Equal(t, search.EntityBusiness, entities[1].Type)
+	require.Equal(t, "SSI", entities[1].SanctionsInfo.List)
+
+	require.Equal(t, search.EntityPerson, entities[2].Type)
+	require.NotNil(t, entities[2].Person)
+	require.Equal(t, "DTC", entities[2].SanctionsInfo.List)
+}
+
+func TestISN_ToEntity(t *testing.T) {
+	person := ISN_ToEntity(ISN{
+		EntityID: "10001",
+		Name:     "KHAN, Abdul Qadeer",
+		Programs: []string{"E.O. 13382 (WMD)"},
+	})
+	require.Equal(t, search.EntityPerson, person.Type)
+	require.NotNil(t, person.Person)
+	require.Equal(t, "ISN", person.SanctionsInfo.List)
+	require.Equal(t, []string{"E.O. 13382 (WMD)"}, person.SanctionsInfo.Programs)
+	require.Empty(t, person.HistoricalInfo)
+
+	business := ISN_ToEntity(ISN{
+		EntityID: "10002",
+		Name:     "Dalian Sunny Industries",
+		Remarks:  []string{"Also located in Shanghai"},
+
+		FederalRegisterNotice: "Vol. 83, No. 91, 05/10/2018",
+		StartDate:             "2018-04-30",
+	})
+	require.Equal(t, search.EntityBusiness, business.Type)
+	require.NotNil(t, business.Business)
+	require.Equal(t, "Also located in Shanghai; Federal Register Notice: Vol. 83, No. 91, 05/10/2018", business.SanctionsInfo.Description)
+
+	require.Len(t, business.HistoricalInfo, 1)
+	require.Equal(t, "Start Date", business.HistoricalInfo[0].Type)
+	require.Equal(t, "2018-04-30", business.HistoricalInfo[0].Date.Format(time.DateOnly))
+}
+
+func TestEL_ToEntity(t *testing.T) {
+	entity := EL_ToEntity(EL{
+		ID:                 "1",
+		Name:               "32 Group China Ltd.",
+		Addresses:          []string{"148 Wing Lok Street, Sheung Wang, Hong Kong, HK"},
+		StartDate:          "2015-11-12",
+		LicenseRequirement: "For all items subject to the EAR.",
+		LicensePolicy:      "Presumption of denial.",
+		FRNotice:           "80 FR 69852",
+	})
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Equal(t, "EL", entity.SanctionsInfo.List)
+	require.Equal(t, "Federal Register Notice: 80 FR 69852; License Requirement: For all items subject to the EAR.; License Policy: Presumption of denial.", entity.SanctionsInfo.Description)
+
+	require.Len(t, entity.HistoricalInfo, 1)
+	require.Equal(t, "Start Date", entity.HistoricalInfo[0].Type)
+	require.Equal(t, "2015-11-12", entity.HistoricalInfo[0].Date.Format(time.DateOnly))
+}
+
+func TestMEU_ToEntity(t *testing.T) {
+	entity := MEU_ToEntity(MEU{
+		EntityID:  "2",
+		Name:      "Aviation Industry Corporation of China",
+		Addresses: "Beijing, CN",
+		FRNotice:  "85 FR 83799",
+		StartDate: "2020-12-23",
+		EndDate:   "2021-06-01",
+	})
+	require.Equal(t, "MEU", entity.SanctionsInfo.List)
+	require.Equal(t, "Federal Register Notice: 85 FR 83799", entity.SanctionsInfo.Description)
+
+	require.Len(t, entity.HistoricalInfo, 2)
+	require.Equal(t, "Start Date", entity.HistoricalInfo[0].Type)
+	require.Equal(t, "End Date", entity.HistoricalInfo[1].Type)
+	require.Equal(t, "2021-06-01", entity.HistoricalInfo[1].Date.Format(time.DateOnly))
+}
+
+func TestDTC_ToEntity(t *testing.T) {
+	entity := DTC_ToEntity(DTC{
+		EntityID:              "3",
+		Name:                  "A & C International Trade, Inc.",
+		AlternateNames:        []string{"A&C International"},
+		FederalRegisterNotice: "67 FR 10033",
+	})
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Equal(t, []string{"A&C International"}, entity.Business.AltNames)
+	require.Equal(t, "DTC", entity.SanctionsInfo.List)
+	require.Equal(t, "Federal Register Notice: 67 FR 10033", entity.SanctionsInfo.Description)
+}
+
+func TestIsBusiness(t *testing.T) {
+	cases := map[string]bool{
+		"Yasmin Ahmed":                      false,
+		"KHAN, Abdul Qadeer":                false,
+		"GBNTT":                             true,
+		"Beijing Institute of Technology":   true,
+		"Aero Space Engineering Co., Ltd.":  true,
+		"Tianjin 712 Communication Factory": true,
+		"Joint Stock Company 'Kronstadt'":   true,
+	}
+	for name, expected := range cases {
+		require.Equal(t, expected, isBusiness(name), name)
+	}
+}
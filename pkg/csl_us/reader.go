@@ -34,6 +34,7 @@ func Parse(r io.Reader) (CSL, error) {
 			if errors.Is(err, csv.ErrFieldCount) ||
 				errors.Is(err, csv.ErrBareQuote) ||
 				errors.Is(err, csv.ErrQuote) {
+				report.Skipped++
 				continue
 			}
 			return report, err
@@ -105,6 +106,7 @@ func unmarshalEL(row []string, offset int) EL {
 		id = row[0] // set the ID from the newer CSV format
 	}
 	return EL{
+		Source:             row[SourceIdx+offset],
 		ID:                 id,
 		Name:               row[NameIdx+offset],
 		Addresses:          expandField(row[AddressesIdx+offset]),
@@ -120,6 +122,7 @@ func unmarshalEL(row []string, offset int) EL {
 
 func unmarshalMEU(record []string, offset int) MEU {
 	return MEU{
+		Source:    record[SourceIdx+offset],
 		EntityID:  record[0],
 		Name:      record[NameIdx+offset],
 		Addresses: record[AddressesIdx+offset],
@@ -131,6 +134,7 @@ func unmarshalMEU(record []string, offset int) MEU {
 
 func unmarshalSSI(record []string, offset int) SSI {
 	return SSI{
+		Source:         record[SourceIdx+offset],
 		EntityID:       record[EntityNumberIdx+offset],
 		Type:           record[TypeIdx+offset],
 		Programs:       expandProgramsList(record[ProgramsIdx+offset]),
@@ -146,6 +150,7 @@ func unmarshalSSI(record []string, offset int) SSI {
 
 func unmarshalUVL(record []string, offset int) UVL {
 	return UVL{
+		Source:        record[SourceIdx+offset],
 		EntityID:      record[0],
 		Name:          record[NameIdx+offset],
 		Addresses:     expandField(record[AddressesIdx+offset]),
@@ -156,6 +161,7 @@ func unmarshalUVL(record []string, offset int) UVL {
 
 func unmarshalISN(record []string, offset int) ISN {
 	return ISN{
+		Source:                record[SourceIdx+offset],
 		EntityID:              record[0],
 		Programs:              expandProgramsList(record[ProgramsIdx+offset]),
 		Name:                  record[NameIdx+offset],
@@ -170,6 +176,7 @@ func unmarshalISN(record []string, offset int) ISN {
 
 func unmarshalFSE(record []string, offset int) FSE {
 	return FSE{
+		Source:        record[SourceIdx+offset],
 		EntityID:      record[0],
 		EntityNumber:  record[EntityNumberIdx+offset],
 		Type:          record[TypeIdx+offset],
@@ -186,6 +193,7 @@ func unmarshalFSE(record []string, offset int) FSE {
 
 func unmarshalPLC(record []string, offset int) PLC {
 	return PLC{
+		Source:         record[SourceIdx+offset],
 		EntityID:       record[0],
 		EntityNumber:   record[EntityNumberIdx+offset],
 		Type:           record[TypeIdx+offset],
@@ -203,6 +211,7 @@ func unmarshalPLC(record []string, offset int) PLC {
 
 func unmarshalCAP(record []string, offset int) CAP {
 	return CAP{
+		Source:         record[SourceIdx+offset],
 		EntityID:       record[0],
 		EntityNumber:   record[EntityNumberIdx+offset],
 		Type:           record[TypeIdx+offset],
@@ -219,6 +228,7 @@ func unmarshalCAP(record []string, offset int) CAP {
 
 func unmarshalNS_MBS(record []string, offset int) NS_MBS {
 	return NS_MBS{
+		Source:         record[SourceIdx+offset],
 		EntityID:       record[0],
 		EntityNumber:   record[EntityNumberIdx+offset],
 		Type:           record[TypeIdx+offset],
@@ -234,6 +244,7 @@ func unmarshalNS_MBS(record []string, offset int) NS_MBS {
 
 func unmarshalCMIC(record []string, offset int) CMIC {
 	return CMIC{
+		Source:         record[SourceIdx+offset],
 		EntityID:       record[0],
 		EntityNumber:   record[EntityNumberIdx+offset],
 		Type:           record[TypeIdx+offset],
@@ -250,6 +261,7 @@ func unmarshalCMIC(record []string, offset int) CMIC {
 
 func unmarshalDTC(record []string, offset int) DTC {
 	return DTC{
+		Source:                record[SourceIdx+offset],
 		EntityID:              record[0],
 		Name:                  record[NameIdx+offset],
 		FederalRegisterNotice: record[FRNoticeIdx+offset],
@@ -75,6 +75,7 @@ func Test_unmarshalEL(t *testing.T) {
 	record := []string{"Entity List (EL) - Bureau of Industry and Security", "", "", "", "GBNTT", "", "No. 34 Mansour Street, Tehran, IR", "73 FR 54506", "2008-09-22", "", "",
 		"For all items subject to the EAR (See §744.11 of the EAR)", "Presumption of denial", "", "", "", "", "", "", "", "http://bit.ly/1L47xrV", "", "", "", "", "", "http://bit.ly/1L47xrV", ""}
 	expectedEL := EL{
+		Source:             "Entity List (EL) - Bureau of Industry and Security",
 		Name:               "GBNTT",
 		AlternateNames:     nil,
 		Addresses:          []string{"No. 34 Mansour Street, Tehran, IR"},
@@ -104,6 +105,7 @@ d54346ef81802673c1b1daeb2ca8bd5d13755abd,Military End User (MEU) List - Bureau o
 	require.Len(t, report.MEUs, 3)
 
 	require.Equal(t, MEU{
+		Source:    "Military End User (MEU) List - Bureau of Industry and Security",
 		EntityID:  "26744194bd9b5cbec49db6ee29a4b53c697c7420",
 		Name:      "AECC Aviation Power Co. Ltd.",
 		Addresses: "Xiujia Bay, Weiyong Dt, Xian, 710021, CN",
@@ -113,6 +115,7 @@ d54346ef81802673c1b1daeb2ca8bd5d13755abd,Military End User (MEU) List - Bureau o
 	}, report.MEUs[0])
 
 	require.Equal(t, MEU{
+		Source:    "Military End User (MEU) List - Bureau of Industry and Security",
 		EntityID:  "d54346ef81802673c1b1daeb2ca8bd5d13755abd",
 		Name:      "AECC China Gas Turbine Establishment",
 		Addresses: "No. 1 Hangkong Road, Mianyang, Sichuan, CN",
@@ -131,6 +134,7 @@ func Test_unmarshalSSI(t *testing.T) {
 		"", "", "", "", "http://bit.ly/1MLgou0", "1027700049486, Registration ID; 00044463, Government Gazette Number; 7706061801, Tax ID No.; transneft@ak.transneft.ru, Email Address; www.transneft.ru, Website; Subject to Directive 2, Executive Order 13662 Directive Determination -",
 	}
 	expectedSSI := SSI{
+		Source:         "Sectoral Sanctions Identifications List (SSI) - Treasury Department",
 		EntityID:       "17254",
 		Type:           "Entity",
 		Programs:       []string{"UKRAINE-EO13662", "SYRIA"},
@@ -157,6 +161,7 @@ func Test_unmarshalUVL(t *testing.T) {
 		"http://bit.ly/1Qi4R7Z", "",
 	}
 	expectedUVL := UVL{
+		Source:        "Unverified List (UVL) - Bureau of Industry and Security",
 		EntityID:      "f15fa805ff4ac5e09026f5e78011a1bb6b26dec2",
 		Name:          "Atlas Sanatgaran",
 		Addresses:     []string{"Komitas 26/114, Yerevan, Armenia, AM"},
@@ -178,6 +183,7 @@ func Test_unmarshalISN(t *testing.T) {
 		"", "", "", "", "", "", "", "", "", "", "Associated with the A.Q. Khan Network", "http://bit.ly/1NuVFxV", "ZAMAN; Haydar", "", "", "", "", "http://bit.ly/1NuVFxV", "",
 	}
 	expectedISN := ISN{
+		Source:                "Nonproliferation Sanctions (ISN) - State Department",
 		EntityID:              "2d2db09c686e4829d0ef1b0b04145eec3d42cd88",
 		Programs:              []string{"E.O. 13382", "Export-Import Bank Act", "Nuclear Proliferation Prevention Act"},
 		Name:                  "Abdul Qadeer Khan",
@@ -203,6 +209,7 @@ func Test_unmarshalFSE(t *testing.T) {
 		"http://bit.ly/1N1docf", "CH, X0906223, Passport",
 	}
 	expectedFSE := FSE{
+		Source:        "Foreign Sanctions Evaders (FSE) - Treasury Department",
 		EntityID:      "17526",
 		EntityNumber:  "17526",
 		Type:          "Individual",
@@ -231,6 +238,7 @@ func Test_unmarshalPLC(t *testing.T) {
 	}
 
 	expectedPLC := PLC{
+		Source:         "Palestinian Legislative Council List (PLC) - Treasury Department",
 		EntityID:       "9702",
 		EntityNumber:   "9702",
 		Type:           "Individual",
@@ -262,6 +270,7 @@ func Test_unmarshalCAP(t *testing.T) {
 	}
 
 	expectedCAP := CAP{
+		Source:        "Capta List (CAP) - Treasury Department",
 		EntityID:      "20002",
 		EntityNumber:  "20002",
 		Type:          "Entity",
@@ -299,6 +308,7 @@ func Test_unmarshalDTC(t *testing.T) {
 	}
 
 	expectedDTC := DTC{
+		Source:                "ITAR Debarred (DTC) - State Department",
 		EntityID:              "d44d88d0265d93927b9ff1c13bbbb7c7db64142c",
 		Name:                  "Yasmin Ahmed",
 		FederalRegisterNotice: "69 FR 17468",
@@ -324,6 +334,7 @@ func Test_unmarshalCMIC(t *testing.T) {
 	}
 
 	expectedCMIC := CMIC{
+		Source:         "Non-SDN Chinese Military-Industrial Complex Companies List (CMIC) - Treasury Department",
 		EntityID:       "32091",
 		EntityNumber:   "32091",
 		Type:           "Entity",
@@ -356,6 +367,7 @@ func Test_unmarshalNS_MBS(t *testing.T) {
 	}
 
 	expectedNS_MBS := NS_MBS{
+		Source:         "Non-SDN Menu-Based Sanctions List (NS-MBS List) - Treasury Department",
 		EntityID:       "17016",
 		EntityNumber:   "17016",
 		Type:           "Entity",
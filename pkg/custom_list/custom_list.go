@@ -0,0 +1,70 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package custom_list reads internally maintained watchlists from CSV and JSON files.
+//
+// Each row (or JSON object) becomes one search.Entity. Columns are matched to entity fields by
+// name, which can be overridden with a Columns mapping. Field and column names are case-insensitive.
+//
+//	Field            Entity field                Notes
+//	sourceID         SourceID                    defaults to "<filename>:<row>"
+//	name             Name
+//	type             Type                        person, business, organization, vessel or aircraft (default: business)
+//	altNames         AltNames
+//	gender           Person.Gender
+//	birthDate        Person.BirthDate            YYYY-MM-DD, YYYY-MM or YYYY
+//	titles           Titles
+//	governmentIDs    Person.GovernmentIDs        "type:value" (e.g. "passport:X123"), Identifier for other types
+//	address          Addresses[0].Line1
+//	city             Addresses[0].City
+//	state            Addresses[0].State
+//	postalCode       Addresses[0].PostalCode
+//	country          Addresses[0].Country
+//	emails           Contact.EmailAddresses
+//	phones           Contact.PhoneNumbers
+//	websites         Contact.Websites
+//	cryptoAddresses  CryptoAddresses             "currency:address" (e.g. "XBT:x123456")
+//	programs         SanctionsInfo.Programs
+//	description      SanctionsInfo.Description
+//	imoNumber        Vessel.IMONumber
+//	callSign         Vessel.CallSign
+//	flag             Vessel.Flag, Aircraft.Flag
+//	serialNumber     Aircraft.SerialNumber
+//
+// Fields which hold several values (altNames, titles, governmentIDs, emails, phones, websites,
+// cryptoAddresses and programs) are separated with ";". JSON arrays are also accepted.
+package custom_list
+
+import (
+	"strings"
+)
+
+// Fields are the entity fields which columns can be mapped onto
+var Fields = []string{
+	"sourceID", "name", "type", "altNames", "gender", "birthDate", "titles", "governmentIDs",
+	"address", "city", "state", "postalCode", "country",
+	"emails", "phones", "websites", "cryptoAddresses",
+	"programs", "description",
+	"imoNumber", "callSign", "flag", "serialNumber",
+}
+
+// Columns maps entity fields to the column names used in a list's files.
+// Fields which are not mapped are read from a column with the same name.
+type Columns map[string]string
+
+func (c Columns) column(field string) string {
+	for f, column := range c {
+		if strings.EqualFold(f, field) {
+			return strings.ToLower(strings.TrimSpace(column))
+		}
+	}
+	return strings.ToLower(field)
+}
+
+// Record is a single row from a file
+type Record struct {
+	Filename string            `json:"filename"`
+	Row      int               `json:"row"`
+	Values   map[string]string `json:"values"` // keyed by lowercase column name
+}
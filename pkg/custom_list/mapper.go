@@ -0,0 +1,241 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package custom_list
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// ConvertRecords maps each record into a search.Entity published under source
+func ConvertRecords(source search.SourceList, columns Columns, records []Record) []search.Entity[search.Value] {
+	out := make([]search.Entity[search.Value], 0, len(records))
+	for _, record := range records {
+		entity := ToEntity(source, columns, record)
+		if entity.Name == "" {
+			continue
+		}
+		out = append(out, entity)
+	}
+	return out
+}
+
+func ToEntity(source search.SourceList, columns Columns, record Record) search.Entity[search.Value] {
+	value := func(field string) string {
+		return strings.TrimSpace(record.Values[columns.column(field)])
+	}
+	values := func(field string) []string {
+		return splitValues(value(field))
+	}
+
+	out := search.Entity[search.Value]{
+		Name:       value("name"),
+		Type:       mapEntityType(value("type")),
+		Source:     source,
+		SourceID:   value("sourceID"),
+		SourceData: record,
+	}
+	if out.SourceID == "" {
+		out.SourceID = fmt.Sprintf("%s:%d", record.Filename, record.Row)
+	}
+
+	altNames := values("altNames")
+	govIDs := values("governmentIDs")
+
+	switch out.Type {
+	case search.EntityPerson:
+		out.Person = &search.Person{
+			Name:          out.Name,
+			AltNames:      altNames,
+			Gender:        mapGender(value("gender")),
+			BirthDate:     parseDate(value("birthDate")),
+			Titles:        values("titles"),
+			GovernmentIDs: mapGovernmentIDs(govIDs, value("country")),
+		}
+
+	case search.EntityBusiness:
+		out.Business = &search.Business{
+			Name:       out.Name,
+			AltNames:   altNames,
+			Identifier: mapIdentifiers(govIDs, value("country")),
+		}
+
+	case search.EntityOrganization:
+		out.Organization = &search.Organization{
+			Name:       out.Name,
+			AltNames:   altNames,
+			Identifier: mapIdentifiers(govIDs, value("country")),
+		}
+
+	case search.EntityVessel:
+		out.Vessel = &search.Vessel{
+			Name:      out.Name,
+			AltNames:  altNames,
+			IMONumber: value("imoNumber"),
+			CallSign:  value("callSign"),
+			Flag:      value("flag"),
+		}
+
+	case search.EntityAircraft:
+		out.Aircraft = &search.Aircraft{
+			Name:         out.Name,
+			AltNames:     altNames,
+			Flag:         value("flag"),
+			SerialNumber: value("serialNumber"),
+		}
+	}
+	out.Titles = values("titles")
+
+	address := search.Address{
+		Line1:      value("address"),
+		City:       value("city"),
+		State:      value("state"),
+		PostalCode: value("postalCode"),
+		Country:    value("country"),
+	}
+	if address != (search.Address{}) {
+		out.Addresses = []search.Address{address}
+	}
+
+	out.Contact = search.ContactInfo{
+		EmailAddresses: values("emails"),
+		PhoneNumbers:   values("phones"),
+		Websites:       values("websites"),
+	}
+	out.CryptoAddresses = mapCryptoAddresses(values("cryptoAddresses"))
+
+	programs, description := values("programs"), value("description")
+	if len(programs) > 0 || description != "" {
+		out.SanctionsInfo = &search.SanctionsInfo{
+			Programs:    programs,
+			Description: description,
+		}
+	}
+
+	return out
+}
+
+func splitValues(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func mapEntityType(value string) search.EntityType {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "person", "individual":
+		return search.EntityPerson
+	case "organization":
+		return search.EntityOrganization
+	case "vessel":
+		return search.EntityVessel
+	case "aircraft":
+		return search.EntityAircraft
+	}
+	return search.EntityBusiness
+}
+
+func mapGender(value string) search.Gender {
+	switch strings.ToLower(value) {
+	case "m", "male":
+		return search.GenderMale
+	case "f", "female":
+		return search.GenderFemale
+	}
+	return search.GenderUnknown
+}
+
+var (
+	dateFormats = []string{"2006-01-02", "2006-01", "2006"}
+)
+
+func parseDate(value string) *time.Time {
+	for _, format := range dateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return &tt
+		}
+	}
+	return nil
+}
+
+// splitPair separates "key:value", returning an empty key when there's no separator
+func splitPair(value string) (string, string) {
+	key, rest, found := strings.Cut(value, ":")
+	if !found {
+		return "", strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(rest)
+}
+
+func mapGovernmentIDs(values []string, country string) []search.GovernmentID {
+	var out []search.GovernmentID
+	for _, v := range values {
+		idType, identifier := splitPair(v)
+		if identifier == "" {
+			continue
+		}
+		out = append(out, search.GovernmentID{
+			Type:       mapGovernmentIDType(idType),
+			Country:    country,
+			Identifier: identifier,
+		})
+	}
+	return out
+}
+
+func mapGovernmentIDType(value string) search.GovernmentIDType {
+	value = strings.ToLower(value)
+	for _, idType := range []search.GovernmentIDType{
+		search.GovernmentIDPassport, search.GovernmentIDDriversLicense, search.GovernmentIDNational,
+		search.GovernmentIDTax, search.GovernmentIDSSN, search.GovernmentIDCedula, search.GovernmentIDCURP,
+		search.GovernmentIDCUIT, search.GovernmentIDElectoral, search.GovernmentIDBusinessRegisration,
+		search.GovernmentIDCommercialRegistry, search.GovernmentIDBirthCert, search.GovernmentIDRefugee,
+		search.GovernmentIDDiplomaticPass, search.GovernmentIDPersonalID,
+	} {
+		if value == string(idType) {
+			return idType
+		}
+	}
+	return search.GovernmentIDPersonalID
+}
+
+func mapIdentifiers(values []string, country string) []search.Identifier {
+	var out []search.Identifier
+	for _, v := range values {
+		name, identifier := splitPair(v)
+		if identifier == "" {
+			continue
+		}
+		out = append(out, search.Identifier{
+			Name:       name,
+			Country:    country,
+			Identifier: identifier,
+		})
+	}
+	return out
+}
+
+func mapCryptoAddresses(values []string) []search.CryptoAddress {
+	var out []search.CryptoAddress
+	for _, v := range values {
+		currency, address := splitPair(v)
+		if address == "" {
+			continue
+		}
+		out = append(out, search.CryptoAddress{
+			Currency: strings.ToUpper(currency),
+			Address:  address,
+		})
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package custom_list
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertRecords(t *testing.T) {
+	records, err := ReadDir(filepath.Join("testdata", "customers"))
+	require.NoError(t, err)
+
+	columns := Columns{
+		"sourceid":      "Customer ID",
+		"name":          "Full Name",
+		"type":          "Entity Type",
+		"altNames":      "Other Names",
+		"birthdate":     "DOB",
+		"gender":        "Sex",
+		"governmentIDs": "IDs",
+		"address":       "Street",
+		"emails":        "Email",
+		"description":   "Reason",
+	}
+	source := search.SourceList("internal_blocklist")
+
+	entities := ConvertRecords(source, columns, records)
+	require.Len(t, entities, 2) // the blank CSV row is skipped
+
+	for _, entity := range entities {
+		require.Equal(t, source, entity.Source)
+	}
+
+	person := entities[0]
+	require.Equal(t, "C-1001", person.SourceID)
+	require.Equal(t, "John Michael Doe", person.Name)
+	require.Equal(t, search.EntityPerson, person.Type)
+	require.Equal(t, []string{"Johnny Doe", "J. M. Doe"}, person.Person.AltNames)
+	require.Equal(t, search.GenderMale, person.Person.Gender)
+	require.Equal(t, "1980-04-02", person.Person.BirthDate.Format(time.DateOnly))
+	expectedIDs := []search.GovernmentID{
+		{Type: search.GovernmentIDPassport, Country: "US", Identifier: "X1234567"},
+		{Type: search.GovernmentIDSSN, Country: "US", Identifier: "123-45-6789"},
+	}
+	require.Equal(t, expectedIDs, person.Person.GovernmentIDs)
+	require.Equal(t, []search.Address{{Line1: "1 Main St", City: "Springfield", Country: "US"}}, person.Addresses)
+	require.Equal(t, []string{"jdoe@example.com"}, person.Contact.EmailAddresses)
+	require.Equal(t, "Chargeback fraud", person.SanctionsInfo.Description)
+
+	business := entities[1]
+	require.Equal(t, search.EntityBusiness, business.Type)
+	require.Equal(t, []search.Identifier{{Name: "registration number", Country: "DE", Identifier: "DE-998877"}}, business.Business.Identifier)
+
+	// Columns default to the field names
+	records, err = ReadDir(filepath.Join("testdata", "fraud"))
+	require.NoError(t, err)
+
+	entities = ConvertRecords(source, nil, records)
+	require.Len(t, entities, 3)
+
+	org := entities[0]
+	require.Equal(t, "extra.ndjson:1", org.SourceID)
+	require.Equal(t, search.EntityOrganization, org.Type)
+	require.Equal(t, []string{"northernlights.example.org"}, org.Contact.Websites)
+
+	jane := entities[1]
+	require.Equal(t, "FR-1", jane.SourceID)
+	require.Equal(t, search.EntityPerson, jane.Type)
+	require.Equal(t, []string{"J. Roe", "Janie Roe"}, jane.Person.AltNames)
+	require.Equal(t, search.GenderFemale, jane.Person.Gender)
+	require.Equal(t, []string{"FRAUD-RING-7"}, jane.SanctionsInfo.Programs)
+	require.Equal(t, []search.CryptoAddress{{Currency: "XBT", Address: "1AbCdEf"}}, jane.CryptoAddresses)
+
+	vessel := entities[2]
+	require.Equal(t, search.EntityVessel, vessel.Type)
+	require.Equal(t, "9187629", vessel.Vessel.IMONumber)
+	require.Equal(t, "PA", vessel.Vessel.Flag)
+}
@@ -0,0 +1,215 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package custom_list
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReadDir reads every .csv, .json, .ndjson and .jsonl file within dir
+func ReadDir(dir string) ([]Record, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("custom list: %w", err)
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+	sort.Strings(filenames)
+
+	var out []Record
+	for _, filename := range filenames {
+		var read func(filename string, r io.Reader) ([]Record, error)
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			read = ReadCSV
+		case ".json", ".ndjson", ".jsonl":
+			read = ReadJSON
+		default:
+			continue
+		}
+
+		fd, err := os.Open(filepath.Join(dir, filename))
+		if err != nil {
+			return nil, fmt.Errorf("custom list: %w", err)
+		}
+		records, err := read(filename, fd)
+		fd.Close()
+		if err != nil {
+			return nil, fmt.Errorf("custom list %s: %w", filename, err)
+		}
+		out = append(out, records...)
+	}
+	return out, nil
+}
+
+// ReadCSV reads a CSV file whose first row contains the column names
+func ReadCSV(filename string, r io.Reader) ([]Record, error) {
+	reader := NewCSVReader(filename, r)
+
+	var out []Record
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+// CSVReader reads records one at a time from a CSV file whose first row contains the column names
+type CSVReader struct {
+	filename string
+	reader   *csv.Reader
+	header   []string
+	row      int
+}
+
+func NewCSVReader(filename string, r io.Reader) *CSVReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return &CSVReader{
+		filename: filename,
+		reader:   reader,
+	}
+}
+
+// Read returns the next record, or io.EOF after the last one
+func (r *CSVReader) Read() (Record, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return Record{}, io.EOF
+			}
+			return Record{}, fmt.Errorf("reading header: %w", err)
+		}
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		}
+		r.header = header
+	}
+
+	r.row++
+	line, err := r.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("row %d: %w", r.row, err)
+	}
+
+	record := Record{
+		Filename: r.filename,
+		Row:      r.row,
+		Values:   make(map[string]string, len(r.header)),
+	}
+	for i, value := range line {
+		if i < len(r.header) && r.header[i] != "" {
+			record.Values[r.header[i]] = strings.TrimSpace(value)
+		}
+	}
+	return record, nil
+}
+
+// ReadJSON reads either an array of objects or newline delimited objects. Numbers and booleans are
+// kept as their text and arrays are joined with ";".
+func ReadJSON(filename string, r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var objects []map[string]any
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+
+	if first == '[' {
+		if err := dec.Decode(&objects); err != nil {
+			return nil, fmt.Errorf("decoding: %w", err)
+		}
+	} else {
+		for {
+			var object map[string]any
+			if err := dec.Decode(&object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("decoding object %d: %w", len(objects)+1, err)
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	out := make([]Record, 0, len(objects))
+	for i, object := range objects {
+		record := Record{
+			Filename: filename,
+			Row:      i + 1,
+			Values:   make(map[string]string, len(object)),
+		}
+		for key, value := range object {
+			record.Values[strings.ToLower(strings.TrimSpace(key))] = jsonString(value)
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		r.ReadByte()
+	}
+}
+
+func jsonString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		var parts []string
+		for _, elm := range v {
+			if s := jsonString(elm); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ";")
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package custom_list
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDir(t *testing.T) {
+	records, err := ReadDir(filepath.Join("testdata", "customers"))
+	require.NoError(t, err)
+	require.Len(t, records, 3) // README.txt is ignored
+
+	require.Equal(t, "former_customers.csv", records[0].Filename)
+	require.Equal(t, 1, records[0].Row)
+	require.Equal(t, "John Michael Doe", records[0].Values["full name"])
+
+	// files are read in name order
+	records, err = ReadDir(filepath.Join("testdata", "fraud"))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, "extra.ndjson", records[0].Filename)
+	require.Equal(t, "Northern Lights Charity", records[0].Values["name"])
+	require.Equal(t, "fraud_rings.json", records[1].Filename)
+	require.Equal(t, "J. Roe;Janie Roe", records[1].Values["altnames"])
+	require.Equal(t, "9187629", records[2].Values["imonumber"])
+
+	_, err = ReadDir(filepath.Join("testdata", "missing"))
+	require.Error(t, err)
+}
+
+func TestCSVReader(t *testing.T) {
+	reader := NewCSVReader("list.csv", strings.NewReader("\ufeffName, Country\nJohn Doe, US\nJane Doe\n"))
+
+	record, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, Record{Filename: "list.csv", Row: 1, Values: map[string]string{"name": "John Doe", "country": "US"}}, record)
+
+	record, err = reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, 2, record.Row)
+	require.Equal(t, map[string]string{"name": "Jane Doe"}, record.Values)
+
+	_, err = reader.Read()
+	require.ErrorIs(t, err, io.EOF)
+
+	// malformed rows are reported with their row number
+	reader = NewCSVReader("list.csv", strings.NewReader("name\n\"John\n"))
+	_, err = reader.Read()
+	require.ErrorContains(t, err, "row 1:")
+
+	// empty files have no records
+	_, err = NewCSVReader("list.csv", strings.NewReader("")).Read()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadJSON_NDJSON(t *testing.T) {
+	input := `{"name": "A", "enabled": true}` + "\n\n" + `{"name": "B"}`
+	records, err := ReadJSON("list.ndjson", strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "true", records[0].Values["enabled"])
+	require.Equal(t, 2, records[1].Row)
+
+	_, err = ReadJSON("list.ndjson", strings.NewReader(`{"name": `))
+	require.Error(t, err)
+
+	records, err = ReadJSON("empty.json", strings.NewReader("  \n"))
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,152 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// cache stores downloaded files and the headers needed to request them conditionally
+type cache struct {
+	dir string
+}
+
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	// SHA256 is the hex encoded hash of the file's content
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// paths returns where a file's content and headers are kept. The URL is part of the name
+// as lists can download files with the same name from different addresses.
+func (c cache) paths(filename, downloadURL string) (string, string) {
+	sum := sha256.Sum256([]byte(downloadURL))
+	base := filepath.Join(c.dir, fmt.Sprintf("%s-%s", hex.EncodeToString(sum[:8]), filepath.Base(filename)))
+	return base, base + ".json"
+}
+
+// lookup returns the cached headers for a file, or nil when it isn't cached
+func (c cache) lookup(filename, downloadURL string) *cacheEntry {
+	contentPath, entryPath := c.paths(filename, downloadURL)
+	if _, err := os.Stat(contentPath); err != nil {
+		return nil
+	}
+
+	bs, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(bs, &entry); err != nil || entry.URL != downloadURL {
+		return nil
+	}
+	return &entry
+}
+
+// setConditionalHeaders asks the server to only respond with content when the file has changed
+func (e *cacheEntry) setConditionalHeaders(req *http.Request) {
+	if e == nil {
+		return
+	}
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+func (c cache) open(filename string, entry *cacheEntry) (io.ReadCloser, error) {
+	contentPath, _ := c.paths(filename, entry.URL)
+	fd, err := os.Open(contentPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening cached %s: %w", filename, err)
+	}
+	return &cachedFile{ReadCloser: fd, notModified: true, sha256: entry.SHA256}, nil
+}
+
+// store saves the response body and headers, then returns the saved copy of the file
+func (c cache) store(filename, downloadURL string, resp *http.Response) (io.ReadCloser, error) {
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	contentPath, entryPath := c.paths(filename, downloadURL)
+
+	fd, err := os.CreateTemp(c.dir, filepath.Base(contentPath)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("creating cached %s: %w", filename, err)
+	}
+	defer os.Remove(fd.Name()) // cleanup when the rename doesn't happen
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(fd, h), resp.Body)
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing cached %s: %w", filename, err)
+	}
+	if err := os.Rename(fd.Name(), contentPath); err != nil {
+		return nil, fmt.Errorf("saving cached %s: %w", filename, err)
+	}
+
+	entry := cacheEntry{
+		URL:          downloadURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		SHA256:       hex.EncodeToString(h.Sum(nil)),
+	}
+	bs, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(entryPath, bs, 0644); err != nil {
+		return nil, fmt.Errorf("saving cached %s headers: %w", filename, err)
+	}
+
+	out, err := os.Open(contentPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening cached %s: %w", filename, err)
+	}
+	return &cachedFile{ReadCloser: out, sha256: entry.SHA256}, nil
+}
+
+// cachedFile is a file read from the cache directory
+type cachedFile struct {
+	io.ReadCloser
+
+	notModified bool
+	sha256      string
+}
+
+// NotModified returns true when file is a cached copy which the server reported
+// hasn't changed since it was last downloaded.
+func NotModified(file io.ReadCloser) bool {
+	cached, ok := file.(*cachedFile)
+	return ok && cached.notModified
+}
+
+// CachedSHA256 returns the hex encoded SHA-256 of a file read from the cache directory,
+// or an empty string for other files and copies cached before hashes were kept.
+func CachedSHA256(file io.ReadCloser) string {
+	cached, ok := file.(*cachedFile)
+	if !ok {
+		return ""
+	}
+	return cached.sha256
+}
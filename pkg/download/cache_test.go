@@ -0,0 +1,100 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloader_GetFiles_Cache(t *testing.T) {
+	var requests, notModified atomic.Int32
+	content := "hello, world"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		etag := `"` + content + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 04 Mar 2024 12:00:00 GMT")
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	dl := New(log.NewTestLogger(), server.Client())
+	dl.Options = Options{CacheDirectory: dir}
+
+	read := func() (string, bool) {
+		t.Helper()
+
+		files, err := dl.GetFiles(context.Background(), "", map[string]string{"file.txt": server.URL + "/file.txt"})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		defer files["file.txt"].Close()
+
+		bs, err := io.ReadAll(files["file.txt"])
+		require.NoError(t, err)
+
+		sum := sha256.Sum256(bs)
+		require.Equal(t, hex.EncodeToString(sum[:]), CachedSHA256(files["file.txt"]))
+
+		return string(bs), NotModified(files["file.txt"])
+	}
+
+	// first download is saved
+	got, unchanged := read()
+	require.Equal(t, "hello, world", got)
+	require.False(t, unchanged)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2) // content and headers
+
+	// the cached copy is used after a 304
+	got, unchanged = read()
+	require.Equal(t, "hello, world", got)
+	require.True(t, unchanged)
+	require.Equal(t, int32(1), notModified.Load())
+
+	// changed files replace the cache
+	content = "updated"
+	got, unchanged = read()
+	require.Equal(t, "updated", got)
+	require.False(t, unchanged)
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestDownloader_GetFiles_NoCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	dl := New(log.NewTestLogger(), server.Client())
+	for i := 0; i < 2; i++ {
+		files, err := dl.GetFiles(context.Background(), "", map[string]string{"file.txt": server.URL})
+		require.NoError(t, err)
+		require.False(t, NotModified(files["file.txt"]))
+		require.Empty(t, CachedSHA256(files["file.txt"]))
+		files["file.txt"].Close()
+	}
+}
@@ -42,6 +42,8 @@ func New(logger log.Logger, httpClient *http.Client) *Downloader {
 type Downloader struct {
 	HTTP   *http.Client
 	Logger log.Logger
+
+	Options Options
 }
 
 // GetFiles will initiate download of all provided files, return an io.ReadCloser to their content
@@ -97,7 +99,7 @@ findfiles:
 			logger := dl.createLogger(filename, downloadURL)
 
 			startTime := time.Now().In(time.UTC)
-			content, err := dl.retryDownload(ctx, downloadURL)
+			content, err := dl.retryDownload(ctx, filename, downloadURL)
 			dur := time.Now().In(time.UTC).Sub(startTime)
 
 			if err != nil {
@@ -105,7 +107,11 @@ findfiles:
 				return
 			}
 
-			logger.Info().Logf("successful download after %v", dur)
+			if NotModified(content) {
+				logger.Info().Logf("file not modified after %v, using cached copy", dur)
+			} else {
+				logger.Info().Logf("successful download after %v", dur)
+			}
 			mu.Lock()
 			out[filename] = content
 			mu.Unlock()
@@ -128,29 +134,79 @@ func (dl *Downloader) createLogger(filename, downloadURL string) log.Logger {
 	})
 }
 
-func (dl *Downloader) retryDownload(ctx context.Context, downloadURL string) (io.ReadCloser, error) {
-	// Allow a couple retries for various sources (some are flakey)
-	for i := 0; i < 3; i++ {
+func (dl *Downloader) retryDownload(ctx context.Context, filename, downloadURL string) (io.ReadCloser, error) {
+	opts := dl.Options
+	logger := dl.createLogger(filename, downloadURL)
+
+	client := dl.HTTP
+	if opts.Client != nil {
+		client = opts.Client
+	}
+
+	var files *cache
+	var cached *cacheEntry
+	if opts.CacheDirectory != "" {
+		files = &cache{dir: opts.CacheDirectory}
+		cached = files.lookup(filename, downloadURL)
+	}
+
+	// Retry with a backoff for various sources (some are flakey or rate limit us)
+	var lastErr error
+	var delay time.Duration
+	for attempt := 1; attempt <= opts.Retry.maxAttempts(); attempt++ {
+		if attempt > 1 {
+			logger.Warn().Logf("retrying download in %v (attempt %d of %d): %v", delay, attempt, opts.Retry.maxAttempts(), lastErr)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		delay = opts.Retry.backoff(attempt)
+
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
 		if err != nil {
-			return nil, dl.Logger.Error().LogErrorf("error building HTTP request: %v", err).Err()
+			return nil, logger.Error().LogErrorf("error building HTTP request: %v", err).Err()
 		}
 		req.Header.Set("User-Agent", fmt.Sprintf("moov-io/watchman:%v", watchman.Version))
 		// in order to get passed europes 406 (Not Accepted)
 		req.Header.Set("accept-language", "en-US,en;q=0.9")
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		cached.setConditionalHeaders(req)
 
-		resp, err := dl.HTTP.Do(req)
-
+		resp, err := client.Do(req)
 		if err != nil {
-			dl.Logger.Error().LogErrorf("err while doing client request: %v", err)
-			time.Sleep(100 * time.Millisecond)
+			lastErr = fmt.Errorf("client request: %w", err)
 			continue
 		}
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			resp.Body.Close()
+			return files.open(filename, cached)
+		}
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			resp.Body.Close()
+
+			lastErr = fmt.Errorf("unexpected HTTP status %s", resp.Status)
+			if !retryableStatus(resp.StatusCode) {
+				return nil, lastErr
+			}
+			if retryAfter, ok := opts.Retry.retryAfter(resp, time.Now()); ok {
+				if retryAfter > opts.Retry.maxRetryAfter() {
+					return nil, fmt.Errorf("%w: Retry-After of %v is longer than %v", lastErr, retryAfter, opts.Retry.maxRetryAfter())
+				}
+				if retryAfter > delay {
+					delay = retryAfter
+				}
+			}
 			continue
 		}
+		if files != nil {
+			return files.store(filename, downloadURL, resp)
+		}
 		return resp.Body, nil
 	}
-	return nil, errors.New("error max retries reached while trying to obtain file")
+	return nil, fmt.Errorf("error max retries reached while trying to obtain file: %w", lastErr)
 }
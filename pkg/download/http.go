@@ -0,0 +1,175 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// HTTPConfig controls the HTTP client used to download lists
+type HTTPConfig struct {
+	// Timeout is the limit for each request. Defaults to 45s.
+	Timeout time.Duration
+
+	// ProxyURL is an HTTP(S) proxy which requests are sent through. The HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY environment variables are used when empty.
+	ProxyURL string
+
+	// CABundleFile is a PEM file of certificates trusted in addition to the system's
+	CABundleFile string
+
+	// Headers are added to every request
+	Headers map[string]string
+
+	Retry RetryConfig
+}
+
+// RetryConfig controls how failed downloads are retried. Network errors, 408, 429 and 5xx
+// responses are retried after an exponential backoff with jitter.
+type RetryConfig struct {
+	// MaxAttempts is how many requests are made for a file. Defaults to 3.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, which doubles on each later retry. Defaults to 500ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the longest delay between attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter is the longest delay asked for by a Retry-After header which is waited for.
+	// The download fails rather than retrying early when a server asks for longer. Defaults to 5m.
+	MaxRetryAfter time.Duration
+}
+
+const (
+	defaultHTTPTimeout    = 45 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMaxRetryAfter  = 5 * time.Minute
+)
+
+// NewHTTPClient returns a client using the proxy and certificates from conf
+func NewHTTPClient(conf HTTPConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if conf.ProxyURL != "" {
+		proxy, err := url.Parse(conf.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy url: %w", err)
+		}
+		if proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", conf.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if conf.CABundleFile != "" {
+		pem, err := os.ReadFile(conf.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func (c RetryConfig) maxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// backoff returns the delay before the given retry (starting at 1) with up to half of it as jitter
+func (c RetryConfig) backoff(retry int) time.Duration {
+	initial := c.InitialBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+
+	delay := initial
+	for i := 1; i < retry && delay < c.maxBackoff(); i++ {
+		delay *= 2
+	}
+	if delay > c.maxBackoff() {
+		delay = c.maxBackoff()
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1)) //nolint:gosec
+}
+
+func (c RetryConfig) maxBackoff() time.Duration {
+	if c.MaxBackoff <= 0 {
+		return defaultMaxBackoff
+	}
+	return c.MaxBackoff
+}
+
+func (c RetryConfig) maxRetryAfter() time.Duration {
+	if c.MaxRetryAfter <= 0 {
+		return defaultMaxRetryAfter
+	}
+	return c.MaxRetryAfter
+}
+
+// retryAfter reads the Retry-After header, which is either a number of seconds or an HTTP date.
+func (c RetryConfig) retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if when, err := http.ParseTime(value); err == nil {
+		delay = when.Sub(now)
+	} else {
+		return 0, false
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
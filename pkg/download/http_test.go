@@ -0,0 +1,197 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloader_GetFiles_Retry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("hello"))
+		}
+	}))
+	defer server.Close()
+
+	dl := New(log.NewTestLogger(), server.Client())
+	dl.Options = Options{
+		Headers: map[string]string{"X-Api-Key": "secret"},
+		Retry: RetryConfig{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+		},
+	}
+
+	files, err := dl.GetFiles(context.Background(), "", map[string]string{"file.txt": server.URL})
+	require.NoError(t, err)
+	require.Contains(t, files, "file.txt")
+
+	bs, err := io.ReadAll(files["file.txt"])
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(bs))
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestDownloader_retryDownload_LongRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	dl := New(log.NewTestLogger(), server.Client())
+	dl.Options = Options{
+		Retry: RetryConfig{
+			MaxAttempts:   3,
+			MaxRetryAfter: time.Minute,
+		},
+	}
+
+	// the download fails rather than retrying before the server asked
+	_, err := dl.retryDownload(context.Background(), "file.txt", server.URL)
+	require.ErrorContains(t, err, "unexpected HTTP status 429 Too Many Requests: Retry-After of 1h0m0s is longer than 1m0s")
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestDownloader_retryDownload_NotRetryable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	dl := New(log.NewTestLogger(), server.Client())
+
+	_, err := dl.retryDownload(context.Background(), "file.txt", server.URL)
+	require.ErrorContains(t, err, "unexpected HTTP status 404 Not Found")
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryConfig(t *testing.T) {
+	var conf RetryConfig
+	require.Equal(t, 3, conf.maxAttempts())
+
+	conf = RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for i := 0; i < 10; i++ {
+		delay := conf.backoff(1)
+		require.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		require.LessOrEqual(t, delay, 100*time.Millisecond)
+
+		delay = conf.backoff(3)
+		require.GreaterOrEqual(t, delay, 200*time.Millisecond)
+		require.LessOrEqual(t, delay, 400*time.Millisecond)
+
+		delay = conf.backoff(20)
+		require.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		require.LessOrEqual(t, delay, time.Second)
+	}
+
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: make(http.Header)}
+
+	_, ok := conf.retryAfter(resp, now)
+	require.False(t, ok)
+
+	resp.Header.Set("Retry-After", "0")
+	delay, ok := conf.retryAfter(resp, now)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), delay)
+
+	resp.Header.Set("Retry-After", now.Add(500*time.Millisecond).Format(http.TimeFormat))
+	delay, ok = conf.retryAfter(resp, now)
+	require.True(t, ok)
+	require.Equal(t, time.Duration(0), delay) // HTTP dates have second precision
+
+	resp.Header.Set("Retry-After", "120") // not limited to MaxBackoff
+	delay, ok = conf.retryAfter(resp, now)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, delay)
+
+	require.Equal(t, 5*time.Minute, conf.maxRetryAfter())
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client, err := NewHTTPClient(HTTPConfig{})
+	require.NoError(t, err)
+	require.Equal(t, 45*time.Second, client.Timeout)
+
+	_, err = NewHTTPClient(HTTPConfig{ProxyURL: "proxy.example.com"})
+	require.ErrorContains(t, err, "invalid proxy url")
+
+	_, err = NewHTTPClient(HTTPConfig{CABundleFile: filepath.Join(t.TempDir(), "missing.pem")})
+	require.ErrorContains(t, err, "reading CA bundle")
+}
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	// the server's certificate isn't trusted by default
+	client, err := NewHTTPClient(HTTPConfig{})
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	require.Error(t, err)
+
+	where := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(where, bundle, 0600))
+
+	client, err = NewHTTPClient(HTTPConfig{CABundleFile: where})
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		w.Write([]byte("from proxy"))
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(HTTPConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://lists.example.com/sdn.csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "from proxy", string(bs))
+	require.Equal(t, "http://lists.example.com/sdn.csv", proxied.Load())
+}
@@ -0,0 +1,25 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"net/http"
+)
+
+// Options change how GetFiles downloads files. Each list's Download function passes them to its Downloader.
+type Options struct {
+	// CacheDirectory keeps a copy of each downloaded file along with its ETag and Last-Modified
+	// headers. Later downloads send conditional requests and reuse the copy when the server
+	// responds with 304 Not Modified.
+	CacheDirectory string
+
+	// Client replaces the Downloader's HTTP client, such as one from NewHTTPClient
+	Client *http.Client
+
+	// Headers are added to every request
+	Headers map[string]string
+
+	Retry RetryConfig
+}
@@ -0,0 +1,36 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dpl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/moov-io/base/log"
+	"github.com/moov-io/watchman/pkg/download"
+)
+
+var (
+	dplURLTemplate = func() string {
+		if v := os.Getenv("DPL_DOWNLOAD_TEMPLATE"); v != "" {
+			return v
+		}
+		return "https://www.bis.doc.gov/dpl/%s"
+	}()
+)
+
+// Download retrieves dpl.txt, which is read with ReadFile.
+func Download(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
+	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
+
+	addrs := map[string]string{
+		"dpl.txt": fmt.Sprintf(dplURLTemplate, "dpl.txt"),
+	}
+
+	return dl.GetFiles(ctx, initialDir, addrs)
+}
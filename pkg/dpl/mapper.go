@@ -0,0 +1,181 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dpl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// ConvertSanctionsData maps each Denied Person into a search.Entity
+//
+// The DPL publishes some rows more than once. Those rows are identical, so repeated SourceIDs
+// are numbered in the order they appear (e.g. "3f2a9c0d41b7e865#1") to keep every entity's
+// SourceID unique without a record's ID depending on where other records are in the file.
+func ConvertSanctionsData(records []DPL) []search.Entity[search.Value] {
+	out := make([]search.Entity[search.Value], 0, len(records))
+	seen := make(map[string]int)
+	for _, record := range records {
+		if record.Name == "" {
+			continue
+		}
+		entity := ToEntity(record)
+		if n := seen[entity.SourceID]; n > 0 {
+			seen[entity.SourceID]++
+			entity.SourceID = fmt.Sprintf("%s#%d", entity.SourceID, n)
+		} else {
+			seen[entity.SourceID] = 1
+		}
+		out = append(out, entity)
+	}
+	return out
+}
+
+func ToEntity(record DPL) search.Entity[search.Value] {
+	name, altNames := splitAliases(cleanName(record.Name))
+
+	out := search.Entity[search.Value]{
+		Name:       name,
+		Source:     search.SourceUSDPL,
+		SourceID:   sourceID(record),
+		SourceData: record,
+	}
+
+	if isBusiness(out.Name) {
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+	} else {
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:     out.Name,
+			AltNames: altNames,
+		}
+	}
+
+	address := search.Address{
+		Line1:      record.StreetAddress,
+		City:       record.City,
+		State:      record.State,
+		PostalCode: record.PostalCode,
+		Country:    record.Country,
+	}
+	if address != (search.Address{}) {
+		out.Addresses = []search.Address{address}
+	}
+
+	out.SanctionsInfo = &search.SanctionsInfo{
+		Description: strings.TrimSpace(strings.Join(nonEmpty(record.Action, record.FRCitation), "; ")),
+	}
+	out.HistoricalInfo = mapHistoricalInfo(record)
+
+	return out
+}
+
+// cleanName removes the extra whitespace some DPL names are published with
+func cleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// splitAliases separates names like "FUYI SUN A/K/A FRANK SUN" into the primary name and its aliases
+func splitAliases(name string) (string, []string) {
+	parts := strings.Split(name, " A/K/A ")
+	if len(parts) == 1 {
+		return name, nil
+	}
+	return strings.TrimSpace(parts[0]), nonEmpty(parts[1:]...)
+}
+
+// sourceID builds an identifier for the record since DPL rows don't have one. It's a hash of
+// every column, as the same person can be listed several times with only the address, dates
+// or Federal Register citation differing.
+func sourceID(record DPL) string {
+	fields := []string{
+		cleanName(record.Name), record.StreetAddress, record.City, record.State, record.Country,
+		record.PostalCode, record.EffectiveDate, record.ExpirationDate, record.StandardOrder,
+		record.LastUpdate, record.Action, record.FRCitation,
+	}
+	h := sha256.New()
+	for _, field := range fields {
+		fmt.Fprintf(h, "%s\n", strings.ToUpper(strings.TrimSpace(field)))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+var businessWords = []string{
+	"AB", "AG", "AIRLINES", "AIRWAYS", "B.V.", "BV", "CO", "CO.", "COMPANY", "CORP", "CORP.",
+	"CORPORATION", "ELECTRONICS", "ENGINEERING", "ENTERPRISE", "ENTERPRISES", "EXPORT", "EXPORTS",
+	"FZE", "GMBH", "GROUP", "IMPORT", "INC", "INC.", "INDUSTRIES", "INTERNATIONAL", "LIMITED", "LLC",
+	"L.L.C.", "LTD", "LTD.", "S.A.", "SA", "SERVICES", "SOLUTIONS", "SRL", "SYSTEMS", "TECHNOLOGIES",
+	"TECHNOLOGY", "TRADING", "TRANSPORT",
+}
+
+// isBusiness guesses if a name belongs to a business since DPL doesn't publish the party's type
+func isBusiness(name string) bool {
+	for _, word := range strings.Fields(strings.ToUpper(name)) {
+		word = strings.Trim(word, ",()")
+		for _, w := range businessWords {
+			if word == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	dplDateFormats = []string{"01/02/2006", "2006-01-02"}
+)
+
+func parseDate(value string) (time.Time, bool) {
+	for _, format := range dplDateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return tt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func mapHistoricalInfo(record DPL) []search.HistoricalInfo {
+	var out []search.HistoricalInfo
+	add := func(infoType, value string) {
+		if value == "" {
+			return
+		}
+		info := search.HistoricalInfo{
+			Type:  infoType,
+			Value: value,
+		}
+		if tt, ok := parseDate(value); ok {
+			info.Date = tt
+		}
+		out = append(out, info)
+	}
+
+	add("Effective Date", record.EffectiveDate)
+	add("Expiration Date", record.ExpirationDate)
+	add("Last Update", record.LastUpdate)
+	add("FR Citation", record.FRCitation)
+
+	return out
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDPL__ToEntity(t *testing.T) {
+	record := DPL{
+		Name:           " ADRIAN MANUEL HERNANDEZ",
+		StreetAddress:  "3037 S. 69TH DRIVE",
+		City:           "PHONEIX",
+		State:          "AZ",
+		PostalCode:     "85043",
+		EffectiveDate:  "10/16/2017",
+		ExpirationDate: "10/13/2020",
+		StandardOrder:  "Y",
+		LastUpdate:     "2017-10-23",
+		Action:         "FR NOTICE ADDED",
+		FRCitation:     "82 F.R. 48792 10/20/2017",
+	}
+	entity := ToEntity(record)
+
+	require.Equal(t, "ADRIAN MANUEL HERNANDEZ", entity.Name)
+	require.Equal(t, search.EntityPerson, entity.Type)
+	require.Equal(t, search.SourceUSDPL, entity.Source)
+	require.Equal(t, "5fc9d375536e5510", entity.SourceID)
+	require.NotNil(t, entity.Person)
+	require.Nil(t, entity.Business)
+
+	expectedAddress := search.Address{Line1: "3037 S. 69TH DRIVE", City: "PHONEIX", State: "AZ", PostalCode: "85043"}
+	require.Equal(t, []search.Address{expectedAddress}, entity.Addresses)
+
+	require.Equal(t, "FR NOTICE ADDED; 82 F.R. 48792 10/20/2017", entity.SanctionsInfo.Description)
+
+	require.Len(t, entity.HistoricalInfo, 4)
+	require.Equal(t, "Effective Date", entity.HistoricalInfo[0].Type)
+	require.Equal(t, "2017-10-16", entity.HistoricalInfo[0].Date.Format(time.DateOnly))
+	require.Equal(t, "2020-10-13", entity.HistoricalInfo[1].Date.Format(time.DateOnly))
+	require.Equal(t, "2017-10-23", entity.HistoricalInfo[2].Date.Format(time.DateOnly))
+	require.Equal(t, search.HistoricalInfo{Type: "FR Citation", Value: "82 F.R. 48792 10/20/2017"}, entity.HistoricalInfo[3])
+}
+
+func TestDPL__ToEntity_Business(t *testing.T) {
+	entity := ToEntity(DPL{Name: "CHITRON ELECTRONICS, INC.", Country: "CN"})
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.NotNil(t, entity.Business)
+	require.Equal(t, "CHITRON ELECTRONICS, INC.", entity.Business.Name)
+
+	entity = ToEntity(DPL{Name: "FUYI SUN A/K/A FRANK SUN"})
+	require.Equal(t, search.EntityPerson, entity.Type)
+	require.Equal(t, "FUYI SUN", entity.Name)
+	require.Equal(t, []string{"FRANK SUN"}, entity.Person.AltNames)
+}
+
+func TestDPL__ConvertSanctionsData(t *testing.T) {
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+
+	records, err := ReadFile(fd)
+	require.NoError(t, err)
+
+	entities := ConvertSanctionsData(records)
+	require.Len(t, entities, len(records))
+
+	var businesses int
+	for _, entity := range entities {
+		if entity.Type == search.EntityBusiness {
+			businesses++
+		}
+	}
+	require.Greater(t, businesses, 50)
+	require.Less(t, businesses, len(entities)/2)
+
+	sourceIDs := make(map[string]search.Value)
+	for _, entity := range entities {
+		require.NotContains(t, sourceIDs, entity.SourceID)
+		sourceIDs[entity.SourceID] = entity.SourceData
+	}
+
+	// reordering the rows keeps each record's SourceID
+	reversed := make([]DPL, len(records))
+	for i := range records {
+		reversed[len(records)-1-i] = records[i]
+	}
+	for _, entity := range ConvertSanctionsData(reversed) {
+		require.Equal(t, sourceIDs[entity.SourceID], entity.SourceData, entity.SourceID)
+	}
+}
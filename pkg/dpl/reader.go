@@ -0,0 +1,78 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dpl
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func ReadFile(fd io.ReadCloser) ([]DPL, error) {
+	records, _, err := ReadFileWithSkipped(fd)
+	return records, err
+}
+
+// ReadFileWithSkipped is ReadFile which also returns how many rows were skipped for missing columns
+func ReadFileWithSkipped(fd io.ReadCloser) ([]DPL, int, error) {
+	if fd == nil {
+		return nil, 0, errors.New("DPL file is empty or missing")
+	}
+	defer fd.Close()
+
+	return parse(fd)
+}
+
+// Parse reads the tab separated rows of dpl.txt. The header row is skipped.
+func Parse(r io.Reader) ([]DPL, error) {
+	records, _, err := parse(r)
+	return records, err
+}
+
+func parse(r io.Reader) ([]DPL, int, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = '\t'
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	var out []DPL
+	var skipped int
+	for {
+		row, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, 0, fmt.Errorf("reading DPL: %w", err)
+		}
+		if len(row) < 12 {
+			if len(row) > 1 {
+				skipped++ // blank lines aren't counted
+			}
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(row[0]), "name") {
+			continue // header
+		}
+
+		out = append(out, DPL{
+			Name:           strings.TrimSpace(row[0]),
+			StreetAddress:  strings.TrimSpace(row[1]),
+			City:           strings.TrimSpace(row[2]),
+			State:          strings.TrimSpace(row[3]),
+			Country:        strings.TrimSpace(row[4]),
+			PostalCode:     strings.TrimSpace(row[5]),
+			EffectiveDate:  strings.TrimSpace(row[6]),
+			ExpirationDate: strings.TrimSpace(row[7]),
+			StandardOrder:  strings.TrimSpace(row[8]),
+			LastUpdate:     strings.TrimSpace(row[9]),
+			Action:         strings.TrimSpace(row[10]),
+			FRCitation:     strings.TrimSpace(row[11]),
+		})
+	}
+	return out, skipped, nil
+}
@@ -0,0 +1,48 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package dpl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDPL__ReadFile(t *testing.T) {
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", "dpl.txt"))
+	require.NoError(t, err)
+
+	records, err := ReadFile(fd)
+	require.NoError(t, err)
+	require.Len(t, records, 546)
+
+	expected := DPL{
+		Name:           "AARON ROBERT HENDERSON",
+		StreetAddress:  "740 JESSIE ST.",
+		City:           "NORTH LIBERTY",
+		State:          "IA",
+		Country:        "US",
+		PostalCode:     "52317",
+		EffectiveDate:  "05/28/2010",
+		ExpirationDate: "09/18/2019",
+		StandardOrder:  "Y",
+		LastUpdate:     "2010-06-09",
+		Action:         "10 YEAR DENIAL/F.R. NOTICE UPDATED",
+		FRCitation:     "75 F.R. 32740 6/9/10",
+	}
+	require.Equal(t, expected, records[3])
+
+	_, err = ReadFile(nil)
+	require.Error(t, err)
+}
+
+func TestDPL__ParseShortRows(t *testing.T) {
+	records, err := Parse(strings.NewReader("Name\tStreet_Address\nJOHN DOE\t1 MAIN ST\n"))
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,184 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ofac
+
+import (
+	"encoding/xml"
+)
+
+// AdvancedSanctions is the OFAC "advanced" XML format (SDN_ADVANCED.XML and CONS_ADVANCED.XML)
+// which publishes each party's identities, features and documents as structured data.
+//
+// Most elements refer to ReferenceValueSets by ID rather than including their value.
+//
+// See: https://ofac.treasury.gov/sdn-list-data-formats-data-schemas
+type AdvancedSanctions struct {
+	XMLName     xml.Name     `xml:"Sanctions"`
+	DateOfIssue AdvancedDate `xml:"DateOfIssue"`
+
+	ReferenceValueSets ReferenceValueSets `xml:"ReferenceValueSets"`
+
+	Locations            []Location            `xml:"Locations>Location"`
+	IDRegDocuments       []IDRegDocument       `xml:"IDRegDocuments>IDRegDocument"`
+	DistinctParties      []DistinctParty       `xml:"DistinctParties>DistinctParty"`
+	ProfileRelationships []ProfileRelationship `xml:"ProfileRelationships>ProfileRelationship"`
+	SanctionsEntries     []SanctionsEntry      `xml:"SanctionsEntries>SanctionsEntry"`
+}
+
+type AdvancedDate struct {
+	Year  int `xml:"Year"`
+	Month int `xml:"Month"`
+	Day   int `xml:"Day"`
+}
+
+type ReferenceValueSets struct {
+	AliasTypes       []ReferenceValue `xml:"AliasTypeValues>AliasType"`
+	Countries        []CountryValue   `xml:"CountryValues>Country"`
+	DetailReferences []ReferenceValue `xml:"DetailReferenceValues>DetailReference"`
+	FeatureTypes     []ReferenceValue `xml:"FeatureTypeValues>FeatureType"`
+	IDRegDocTypes    []ReferenceValue `xml:"IDRegDocTypeValues>IDRegDocType"`
+	LocPartTypes     []ReferenceValue `xml:"LocPartTypeValues>LocPartType"`
+	NamePartTypes    []ReferenceValue `xml:"NamePartTypeValues>NamePartType"`
+	PartySubTypes    []PartySubType   `xml:"PartySubTypeValues>PartySubType"`
+	PartyTypes       []ReferenceValue `xml:"PartyTypeValues>PartyType"`
+	RelationTypes    []ReferenceValue `xml:"RelationTypeValues>RelationType"`
+	SanctionsTypes   []ReferenceValue `xml:"SanctionsTypeValues>SanctionsType"`
+}
+
+type ReferenceValue struct {
+	ID    string `xml:"ID,attr"`
+	Value string `xml:",chardata"`
+}
+
+type CountryValue struct {
+	ID    string `xml:"ID,attr"`
+	ISO2  string `xml:"ISO2,attr"`
+	Value string `xml:",chardata"`
+}
+
+type PartySubType struct {
+	ID          string `xml:"ID,attr"`
+	PartyTypeID string `xml:"PartyTypeID,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type Location struct {
+	ID        string            `xml:"ID,attr"`
+	Countries []LocationCountry `xml:"LocationCountry"`
+	Parts     []LocationPart    `xml:"LocationPart"`
+}
+
+type LocationCountry struct {
+	CountryID string `xml:"CountryID,attr"`
+}
+
+type LocationPart struct {
+	LocPartTypeID string   `xml:"LocPartTypeID,attr"`
+	Values        []string `xml:"LocationPartValue>Value"`
+}
+
+type IDRegDocument struct {
+	ID                string `xml:"ID,attr"`
+	IDRegDocTypeID    string `xml:"IDRegDocTypeID,attr"`
+	IdentityID        string `xml:"IdentityID,attr"`
+	IssuedByCountryID string `xml:"IssuedBy-CountryID,attr"`
+	IDRegistrationNo  string `xml:"IDRegistrationNo"`
+}
+
+type DistinctParty struct {
+	// FixedRef is the same identifier as the SDN CSV files' ent_num
+	FixedRef string    `xml:"FixedRef,attr"`
+	Comment  string    `xml:"Comment"`
+	Profiles []Profile `xml:"Profile"`
+}
+
+type Profile struct {
+	ID             string     `xml:"ID,attr"`
+	PartySubTypeID string     `xml:"PartySubTypeID,attr"`
+	Identities     []Identity `xml:"Identity"`
+	Features       []Feature  `xml:"Feature"`
+}
+
+type Identity struct {
+	ID             string          `xml:"ID,attr"`
+	Primary        bool            `xml:"Primary,attr"`
+	Aliases        []Alias         `xml:"Alias"`
+	NamePartGroups []NamePartGroup `xml:"NamePartGroups>MasterNamePartGroup>NamePartGroup"`
+}
+
+type Alias struct {
+	AliasTypeID     string           `xml:"AliasTypeID,attr"`
+	Primary         bool             `xml:"Primary,attr"`
+	LowQuality      bool             `xml:"LowQuality,attr"`
+	DocumentedNames []DocumentedName `xml:"DocumentedName"`
+}
+
+type DocumentedName struct {
+	Parts []NamePartValue `xml:"DocumentedNamePart>NamePartValue"`
+}
+
+type NamePartValue struct {
+	NamePartGroupID string `xml:"NamePartGroupID,attr"`
+	ScriptID        string `xml:"ScriptID,attr"`
+	Value           string `xml:",chardata"`
+}
+
+type NamePartGroup struct {
+	ID             string `xml:"ID,attr"`
+	NamePartTypeID string `xml:"NamePartTypeID,attr"`
+}
+
+type Feature struct {
+	ID            string           `xml:"ID,attr"`
+	FeatureTypeID string           `xml:"FeatureTypeID,attr"`
+	Versions      []FeatureVersion `xml:"FeatureVersion"`
+}
+
+type FeatureVersion struct {
+	DatePeriods []DatePeriod      `xml:"DatePeriod"`
+	Details     []VersionDetail   `xml:"VersionDetail"`
+	Locations   []VersionLocation `xml:"VersionLocation"`
+}
+
+type DatePeriod struct {
+	Start DateBoundary `xml:"Start"`
+	End   DateBoundary `xml:"End"`
+}
+
+type DateBoundary struct {
+	Approximate bool         `xml:"Approximate,attr"`
+	From        AdvancedDate `xml:"From"`
+	To          AdvancedDate `xml:"To"`
+}
+
+type VersionDetail struct {
+	DetailTypeID      string `xml:"DetailTypeID,attr"`
+	DetailReferenceID string `xml:"DetailReferenceID,attr"`
+	Value             string `xml:",chardata"`
+}
+
+type VersionLocation struct {
+	LocationID string `xml:"LocationID,attr"`
+}
+
+type ProfileRelationship struct {
+	ID             string `xml:"ID,attr"`
+	FromProfileID  string `xml:"From-ProfileID,attr"`
+	ToProfileID    string `xml:"To-ProfileID,attr"`
+	RelationTypeID string `xml:"RelationTypeID,attr"`
+	Former         bool   `xml:"Former,attr"`
+}
+
+type SanctionsEntry struct {
+	ID        string             `xml:"ID,attr"`
+	ProfileID string             `xml:"ProfileID,attr"`
+	ListID    string             `xml:"ListID,attr"`
+	Measures  []SanctionsMeasure `xml:"SanctionsMeasure"`
+}
+
+type SanctionsMeasure struct {
+	SanctionsTypeID string `xml:"SanctionsTypeID,attr"`
+	Comment         string `xml:"Comment"`
+}
@@ -22,6 +22,21 @@ var (
 		"SDN_COMMENTS.CSV", // Specially Designated National Comments
 	}
 
+	consolidatedFilenames = []string{
+		"CONS_ADD.CSV",      // Address
+		"CONS_ALT.CSV",      // Alternate ID
+		"CONS_PRIM.CSV",     // Consolidated (non-SDN) parties
+		"CONS_COMMENTS.CSV", // Consolidated (non-SDN) Comments
+	}
+
+	ofacAdvancedFilenames = []string{
+		"SDN_ADVANCED.XML", // Specially Designated Nationals in the advanced XML format
+	}
+
+	consolidatedAdvancedFilenames = []string{
+		"CONS_ADVANCED.XML", // Consolidated (non-SDN) parties in the advanced XML format
+	}
+
 	ofacURLTemplate = func() string {
 		if v := os.Getenv("OFAC_DOWNLOAD_TEMPLATE"); v != "" {
 			return v
@@ -30,8 +45,9 @@ var (
 	}()
 )
 
-func Download(ctx context.Context, logger log.Logger, initialDir string) (map[string]io.ReadCloser, error) {
+func Download(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
 	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
 
 	addrs := make(map[string]string)
 	for i := range ofacFilenames {
@@ -40,3 +56,42 @@ func Download(ctx context.Context, logger log.Logger, initialDir string) (map[st
 
 	return dl.GetFiles(ctx, initialDir, addrs)
 }
+
+// DownloadConsolidated retrieves the Consolidated (non-SDN) list files, which are read with Read.
+func DownloadConsolidated(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
+	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
+
+	addrs := make(map[string]string)
+	for i := range consolidatedFilenames {
+		addrs[consolidatedFilenames[i]] = fmt.Sprintf(ofacURLTemplate, consolidatedFilenames[i])
+	}
+
+	return dl.GetFiles(ctx, initialDir, addrs)
+}
+
+// DownloadAdvanced retrieves SDN_ADVANCED.XML, which is read with ReadAdvanced.
+func DownloadAdvanced(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
+	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
+
+	addrs := make(map[string]string)
+	for i := range ofacAdvancedFilenames {
+		addrs[ofacAdvancedFilenames[i]] = fmt.Sprintf(ofacURLTemplate, ofacAdvancedFilenames[i])
+	}
+
+	return dl.GetFiles(ctx, initialDir, addrs)
+}
+
+// DownloadConsolidatedAdvanced retrieves CONS_ADVANCED.XML, which is read with ReadAdvanced.
+func DownloadConsolidatedAdvanced(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
+	dl := download.New(logger, download.HTTPClient)
+	dl.Options = opts
+
+	addrs := make(map[string]string)
+	for i := range consolidatedAdvancedFilenames {
+		addrs[consolidatedAdvancedFilenames[i]] = fmt.Sprintf(ofacURLTemplate, consolidatedAdvancedFilenames[i])
+	}
+
+	return dl.GetFiles(ctx, initialDir, addrs)
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+
 	"github.com/moov-io/base/log"
 	"github.com/stretchr/testify/require"
 )
@@ -20,7 +22,7 @@ func TestDownloader(t *testing.T) {
 		return
 	}
 
-	files, err := Download(context.Background(), log.NewNopLogger(), "")
+	files, err := Download(context.Background(), log.NewNopLogger(), "", download.Options{})
 	require.NoError(t, err)
 	require.Len(t, files, 4)
 
@@ -53,7 +55,7 @@ func TestDownloader__initialDir(t *testing.T) {
 	mk(t, "sdn.csv", "file=sdn.csv")
 	mk(t, "dpl.txt", "file=dpl.txt")
 
-	files, err := Download(context.Background(), log.NewNopLogger(), dir)
+	files, err := Download(context.Background(), log.NewNopLogger(), dir, download.Options{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -176,6 +176,16 @@ func GroupIntoEntities(sdns []SDN, addresses []Address, comments []SDNComments,
 	return indices.ProcessSlice(sdns, groups, fn)
 }
 
+// GroupConsolidatedIntoEntities maps records from the Consolidated (non-SDN) files. They are
+// published as search.SourceUSNonSDN so results can be told apart from the SDN list.
+func GroupConsolidatedIntoEntities(records []SDN, addresses []Address, comments []SDNComments, altIds []AlternateIdentity) []search.Entity[search.Value] {
+	entities := GroupIntoEntities(records, addresses, comments, altIds)
+	for i := range entities {
+		entities[i].Source = search.SourceUSNonSDN
+	}
+	return entities
+}
+
 func ToEntity(sdn SDN, addresses []Address, comments []SDNComments, altIds []AlternateIdentity) search.Entity[search.Value] {
 	out := search.Entity[search.Value]{
 		Name:       prepare.ReorderSDNName(sdn.SDNName, sdn.SDNType),
@@ -0,0 +1,542 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ofac
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/internal/indices"
+	"github.com/moov-io/watchman/internal/prepare"
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// GroupAdvancedIntoEntities converts each DistinctParty from the advanced XML format into a search.Entity
+func GroupAdvancedIntoEntities(doc *AdvancedSanctions) []search.Entity[search.Value] {
+	if doc == nil {
+		return nil
+	}
+
+	refs := newAdvancedReferences(doc)
+	fn := func(party DistinctParty) search.Entity[search.Value] {
+		return refs.toEntity(party)
+	}
+
+	groups := runtime.NumCPU() // arbitrary group size // TODO(adam):
+
+	return indices.ProcessSlice(doc.DistinctParties, groups, fn)
+}
+
+// GroupConsolidatedAdvancedIntoEntities converts the Consolidated (non-SDN) list's advanced XML file,
+// whose entities have a Source of search.SourceUSNonSDN.
+func GroupConsolidatedAdvancedIntoEntities(doc *AdvancedSanctions) []search.Entity[search.Value] {
+	entities := GroupAdvancedIntoEntities(doc)
+	for i := range entities {
+		entities[i].Source = search.SourceUSNonSDN
+	}
+	return entities
+}
+
+// advancedReferences resolves the IDs used throughout the advanced XML format
+type advancedReferences struct {
+	aliasTypes       map[string]string
+	countries        map[string]string
+	detailReferences map[string]string
+	featureTypes     map[string]string
+	idRegDocTypes    map[string]string
+	locPartTypes     map[string]string
+	namePartTypes    map[string]string
+	partyTypes       map[string]string
+	partySubTypes    map[string]PartySubType
+	relationTypes    map[string]string
+	sanctionsTypes   map[string]string
+
+	locations     map[string]Location
+	documents     map[string][]IDRegDocument       // keyed by IdentityID
+	relationships map[string][]ProfileRelationship // keyed by From-ProfileID
+	entries       map[string][]SanctionsEntry      // keyed by ProfileID
+	profileNames  map[string]string
+}
+
+func newAdvancedReferences(doc *AdvancedSanctions) *advancedReferences {
+	sets := doc.ReferenceValueSets
+	refs := &advancedReferences{
+		aliasTypes:       referenceMap(sets.AliasTypes),
+		detailReferences: referenceMap(sets.DetailReferences),
+		featureTypes:     referenceMap(sets.FeatureTypes),
+		idRegDocTypes:    referenceMap(sets.IDRegDocTypes),
+		locPartTypes:     referenceMap(sets.LocPartTypes),
+		namePartTypes:    referenceMap(sets.NamePartTypes),
+		partyTypes:       referenceMap(sets.PartyTypes),
+		relationTypes:    referenceMap(sets.RelationTypes),
+		sanctionsTypes:   referenceMap(sets.SanctionsTypes),
+
+		countries:     make(map[string]string),
+		partySubTypes: make(map[string]PartySubType),
+		locations:     make(map[string]Location),
+		documents:     make(map[string][]IDRegDocument),
+		relationships: make(map[string][]ProfileRelationship),
+		entries:       make(map[string][]SanctionsEntry),
+		profileNames:  make(map[string]string),
+	}
+	for _, country := range sets.Countries {
+		refs.countries[country.ID] = strings.TrimSpace(country.Value)
+	}
+	for _, subType := range sets.PartySubTypes {
+		refs.partySubTypes[subType.ID] = subType
+	}
+	for _, loc := range doc.Locations {
+		refs.locations[loc.ID] = loc
+	}
+	for _, document := range doc.IDRegDocuments {
+		refs.documents[document.IdentityID] = append(refs.documents[document.IdentityID], document)
+	}
+	for _, rel := range doc.ProfileRelationships {
+		refs.relationships[rel.FromProfileID] = append(refs.relationships[rel.FromProfileID], rel)
+	}
+	for _, entry := range doc.SanctionsEntries {
+		refs.entries[entry.ProfileID] = append(refs.entries[entry.ProfileID], entry)
+	}
+
+	// Relationships refer to other profiles, so capture every primary name up front
+	for _, party := range doc.DistinctParties {
+		for _, profile := range party.Profiles {
+			name, _, _ := refs.names(profile)
+			refs.profileNames[profile.ID] = name
+		}
+	}
+
+	return refs
+}
+
+func referenceMap(values []ReferenceValue) map[string]string {
+	out := make(map[string]string, len(values))
+	for _, v := range values {
+		out[v.ID] = strings.TrimSpace(v.Value)
+	}
+	return out
+}
+
+func (refs *advancedReferences) toEntity(party DistinctParty) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Source:     search.SourceUSOFAC,
+		SourceID:   party.FixedRef,
+		SourceData: party,
+	}
+	if len(party.Profiles) == 0 {
+		return out
+	}
+	profile := party.Profiles[0]
+
+	name, altNames, formerNames := refs.names(profile)
+	out.Name = name
+
+	switch refs.entityType(profile.PartySubTypeID) {
+	case search.EntityPerson:
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:     name,
+			AltNames: altNames,
+		}
+	case search.EntityVessel:
+		out.Type = search.EntityVessel
+		out.Vessel = &search.Vessel{
+			Name:     name,
+			AltNames: altNames,
+		}
+	case search.EntityAircraft:
+		out.Type = search.EntityAircraft
+		out.Aircraft = &search.Aircraft{
+			Name:     name,
+			AltNames: altNames,
+		}
+	default:
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:     prepare.RemoveCompanyTitles(name),
+			AltNames: altNames,
+		}
+	}
+
+	for _, former := range formerNames {
+		out.HistoricalInfo = append(out.HistoricalInfo, search.HistoricalInfo{
+			Type:  "Former Name",
+			Value: former,
+		})
+	}
+
+	for _, feature := range profile.Features {
+		refs.applyFeature(&out, feature)
+	}
+	for _, identity := range profile.Identities {
+		for _, document := range refs.documents[identity.ID] {
+			refs.applyDocument(&out, document)
+		}
+	}
+
+	for _, rel := range refs.relationships[profile.ID] {
+		aff := search.Affiliation{
+			EntityName: refs.profileNames[rel.ToProfileID],
+			Type:       refs.relationTypes[rel.RelationTypeID],
+		}
+		if rel.Former {
+			aff.Details = "former"
+		}
+		if aff.EntityName != "" {
+			out.Affiliations = append(out.Affiliations, aff)
+		}
+	}
+
+	var programs []string
+	for _, entry := range refs.entries[profile.ID] {
+		for _, measure := range entry.Measures {
+			comment := strings.TrimSpace(measure.Comment)
+			if strings.EqualFold(refs.sanctionsTypes[measure.SanctionsTypeID], "Program") && comment != "" {
+				programs = append(programs, comment)
+			}
+		}
+	}
+	if comment := strings.TrimSpace(party.Comment); len(programs) > 0 || comment != "" || out.SanctionsInfo != nil {
+		if out.SanctionsInfo == nil {
+			out.SanctionsInfo = &search.SanctionsInfo{}
+		}
+		out.SanctionsInfo.Programs = programs
+		out.SanctionsInfo.Description = comment
+	}
+
+	return out
+}
+
+func (refs *advancedReferences) entityType(partySubTypeID string) search.EntityType {
+	subType := refs.partySubTypes[partySubTypeID]
+	switch strings.ToLower(strings.TrimSpace(subType.Value)) {
+	case "vessel":
+		return search.EntityVessel
+	case "aircraft":
+		return search.EntityAircraft
+	}
+	switch strings.ToLower(refs.partyTypes[subType.PartyTypeID]) {
+	case "individual":
+		return search.EntityPerson
+	}
+	return search.EntityBusiness
+}
+
+var (
+	// namePartOrder sorts name parts so individuals read "First Middle Last"
+	namePartOrder = map[string]int{
+		"first name":  0,
+		"middle name": 1,
+		"patronymic":  2,
+		"last name":   3,
+		"matronymic":  4,
+		"maiden name": 5,
+	}
+)
+
+// names returns the primary name, alternate names and former names from each of the profile's identities.
+//
+// Every documented name after the first (e.g. a non-Latin script) is included as an alternate name.
+func (refs *advancedReferences) names(profile Profile) (string, []string, []string) {
+	var primary string
+	var altNames, formerNames []string
+
+	for _, identity := range profile.Identities {
+		groupTypes := make(map[string]string)
+		for _, group := range identity.NamePartGroups {
+			groupTypes[group.ID] = strings.ToLower(refs.namePartTypes[group.NamePartTypeID])
+		}
+
+		for _, alias := range identity.Aliases {
+			for idx, documented := range alias.DocumentedNames {
+				name := joinNameParts(documented.Parts, groupTypes)
+				if name == "" {
+					continue
+				}
+
+				switch {
+				case primary == "" && identity.Primary && alias.Primary && idx == 0:
+					primary = name
+				case strings.EqualFold(refs.aliasTypes[alias.AliasTypeID], "F.K.A."):
+					altNames = append(altNames, name)
+					formerNames = append(formerNames, name)
+				default:
+					altNames = append(altNames, name)
+				}
+			}
+		}
+	}
+
+	return primary, deduplicateStrings(altNames), formerNames
+}
+
+func joinNameParts(parts []NamePartValue, groupTypes map[string]string) string {
+	parts = slices.Clone(parts)
+	slices.SortStableFunc(parts, func(a, b NamePartValue) int {
+		return namePartRank(groupTypes[a.NamePartGroupID]) - namePartRank(groupTypes[b.NamePartGroupID])
+	})
+
+	var values []string
+	for _, part := range parts {
+		if v := strings.TrimSpace(part.Value); v != "" {
+			values = append(values, v)
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+func namePartRank(partType string) int {
+	if rank, exists := namePartOrder[partType]; exists {
+		return rank
+	}
+	return len(namePartOrder)
+}
+
+func (refs *advancedReferences) detailValue(detail VersionDetail) string {
+	if detail.DetailReferenceID != "" {
+		if v, exists := refs.detailReferences[detail.DetailReferenceID]; exists {
+			return v
+		}
+	}
+	return strings.TrimSpace(detail.Value)
+}
+
+func (refs *advancedReferences) applyFeature(out *search.Entity[search.Value], feature Feature) {
+	featureType := strings.ToLower(refs.featureTypes[feature.FeatureTypeID])
+
+	for _, version := range feature.Versions {
+		var value string
+		if len(version.Details) > 0 {
+			value = refs.detailValue(version.Details[0])
+		}
+		var date *time.Time
+		if len(version.DatePeriods) > 0 {
+			date = advancedDateToTime(version.DatePeriods[0].Start.From)
+		}
+
+		switch {
+		case featureType == "location":
+			for _, loc := range version.Locations {
+				if addr := refs.address(loc.LocationID); addr != (search.Address{}) {
+					out.Addresses = append(out.Addresses, addr)
+				}
+			}
+
+		case featureType == "birthdate":
+			if out.Person != nil && out.Person.BirthDate == nil {
+				out.Person.BirthDate = date
+			}
+
+		case featureType == "gender":
+			if out.Person != nil {
+				out.Person.Gender = search.Gender(strings.ToLower(value))
+			}
+
+		case featureType == "title":
+			if value != "" {
+				out.Titles = append(out.Titles, value)
+				if out.Person != nil {
+					out.Person.Titles = append(out.Person.Titles, value)
+				}
+			}
+
+		case featureType == "website":
+			out.Contact.Websites = appendNonEmpty(out.Contact.Websites, value)
+
+		case featureType == "email address":
+			out.Contact.EmailAddresses = appendNonEmpty(out.Contact.EmailAddresses, value)
+
+		case strings.Contains(featureType, "fax"):
+			out.Contact.FaxNumbers = appendNonEmpty(out.Contact.FaxNumbers, value)
+
+		case strings.Contains(featureType, "phone"):
+			out.Contact.PhoneNumbers = appendNonEmpty(out.Contact.PhoneNumbers, value)
+
+		case strings.HasPrefix(featureType, "digital currency address"):
+			_, currency, _ := strings.Cut(refs.featureTypes[feature.FeatureTypeID], " - ")
+			if value != "" {
+				out.CryptoAddresses = append(out.CryptoAddresses, search.CryptoAddress{
+					Currency: strings.TrimSpace(currency),
+					Address:  value,
+				})
+			}
+
+		case strings.HasPrefix(featureType, "secondary sanctions risk"):
+			if out.SanctionsInfo == nil {
+				out.SanctionsInfo = &search.SanctionsInfo{}
+			}
+			out.SanctionsInfo.Secondary = true
+
+		case featureType == "organization established date":
+			if out.Business != nil {
+				out.Business.Created = date
+			}
+
+		case out.Vessel != nil:
+			refs.applyVesselFeature(out.Vessel, featureType, value, date)
+
+		case out.Aircraft != nil:
+			refs.applyAircraftFeature(out.Aircraft, featureType, value, date)
+		}
+	}
+}
+
+func (refs *advancedReferences) applyVesselFeature(vessel *search.Vessel, featureType, value string, date *time.Time) {
+	switch featureType {
+	case "vessel call sign":
+		vessel.CallSign = value
+	case "vessel type":
+		vessel.Type = normalizeVesselType(value)
+	case "vessel flag":
+		vessel.Flag = normalizeCountryCode(value)
+	case "vessel owner":
+		vessel.Owner = value
+	case "vessel tonnage":
+		vessel.Tonnage = parseTonnage(value)
+	case "vessel gross registered tonnage":
+		vessel.GrossRegisteredTonnage = parseTonnage(value)
+	case "vessel year of build":
+		vessel.Built = date
+	case "mmsi":
+		vessel.MMSI = value
+	}
+}
+
+func (refs *advancedReferences) applyAircraftFeature(aircraft *search.Aircraft, featureType, value string, date *time.Time) {
+	switch {
+	case featureType == "aircraft model":
+		aircraft.Model = value
+	case featureType == "aircraft manufacture date":
+		aircraft.Built = date
+	case strings.Contains(featureType, "serial number"):
+		aircraft.SerialNumber = value
+	case featureType == "aircraft flag", featureType == "aircraft operator country":
+		aircraft.Flag = normalizeCountryCode(value)
+	}
+}
+
+func (refs *advancedReferences) applyDocument(out *search.Entity[search.Value], document IDRegDocument) {
+	number := strings.TrimSpace(document.IDRegistrationNo)
+	if number == "" {
+		return
+	}
+	docType := refs.idRegDocTypes[document.IDRegDocTypeID]
+	country := refs.countries[document.IssuedByCountryID]
+
+	lowerType := strings.ToLower(docType)
+	switch {
+	case out.Vessel != nil && strings.HasPrefix(lowerType, "vessel registration"):
+		out.Vessel.IMONumber = strings.TrimSpace(strings.TrimPrefix(number, "IMO"))
+
+	case out.Vessel != nil && lowerType == "mmsi":
+		out.Vessel.MMSI = number
+
+	case out.Aircraft != nil && strings.Contains(lowerType, "serial number"):
+		out.Aircraft.SerialNumber = number
+
+	case out.Person != nil:
+		out.Person.GovernmentIDs = append(out.Person.GovernmentIDs, search.GovernmentID{
+			Type:       advancedGovernmentIDType(lowerType),
+			Country:    country,
+			Identifier: number,
+		})
+
+	case out.Business != nil:
+		out.Business.Identifier = append(out.Business.Identifier, search.Identifier{
+			Name:       docType,
+			Country:    country,
+			Identifier: number,
+		})
+	}
+}
+
+func advancedGovernmentIDType(docType string) search.GovernmentIDType {
+	switch {
+	case strings.Contains(docType, "diplomatic passport"):
+		return search.GovernmentIDDiplomaticPass
+	case strings.Contains(docType, "passport"):
+		return search.GovernmentIDPassport
+	case strings.Contains(docType, "national id"):
+		return search.GovernmentIDNational
+	case strings.Contains(docType, "tax id"):
+		return search.GovernmentIDTax
+	case strings.Contains(docType, "ssn"):
+		return search.GovernmentIDSSN
+	case strings.Contains(docType, "cedula"):
+		return search.GovernmentIDCedula
+	case strings.Contains(docType, "c.u.r.p"):
+		return search.GovernmentIDCURP
+	case strings.Contains(docType, "c.u.i.t"):
+		return search.GovernmentIDCUIT
+	case strings.Contains(docType, "driver"):
+		return search.GovernmentIDDriversLicense
+	case strings.Contains(docType, "electoral"):
+		return search.GovernmentIDElectoral
+	case strings.Contains(docType, "birth certificate"):
+		return search.GovernmentIDBirthCert
+	case strings.Contains(docType, "refugee"):
+		return search.GovernmentIDRefugee
+	}
+	return search.GovernmentIDPersonalID
+}
+
+func (refs *advancedReferences) address(locationID string) search.Address {
+	loc, exists := refs.locations[locationID]
+	if !exists {
+		return search.Address{}
+	}
+
+	var out search.Address
+	var line2 []string
+	for _, part := range loc.Parts {
+		value := strings.TrimSpace(strings.Join(part.Values, " "))
+		switch strings.ToUpper(refs.locPartTypes[part.LocPartTypeID]) {
+		case "ADDRESS1":
+			out.Line1 = value
+		case "ADDRESS2", "ADDRESS3":
+			line2 = appendNonEmpty(line2, value)
+		case "CITY":
+			out.City = value
+		case "STATE/PROVINCE":
+			out.State = value
+		case "POSTAL CODE":
+			out.PostalCode = value
+		}
+	}
+	out.Line2 = strings.Join(line2, ", ")
+
+	if len(loc.Countries) > 0 {
+		out.Country = refs.countries[loc.Countries[0].CountryID]
+	}
+	return out
+}
+
+func advancedDateToTime(date AdvancedDate) *time.Time {
+	if date.Year == 0 {
+		return nil
+	}
+	month, day := date.Month, date.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+
+	tt := time.Date(date.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if invalidDate(tt) {
+		return nil
+	}
+	return &tt
+}
+
+func appendNonEmpty(values []string, value string) []string {
+	if value = strings.TrimSpace(value); value != "" {
+		return append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,116 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ofac
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupAdvancedIntoEntities(t *testing.T) {
+	fd, err := os.Open(filepath.Join("testdata", "sdn_advanced.xml"))
+	require.NoError(t, err)
+
+	doc, err := ReadAdvanced(fd)
+	require.NoError(t, err)
+	require.Equal(t, 2024, doc.DateOfIssue.Year)
+	require.Len(t, doc.DistinctParties, 4)
+
+	entities := GroupAdvancedIntoEntities(doc)
+	require.Len(t, entities, 4)
+
+	find := func(t *testing.T, sourceID string) search.Entity[search.Value] {
+		t.Helper()
+		for _, entity := range entities {
+			if entity.SourceID == sourceID {
+				return entity
+			}
+		}
+		t.Fatalf("entity %s not found", sourceID)
+		return search.Entity[search.Value]{}
+	}
+
+	t.Run("person", func(t *testing.T) {
+		entity := find(t, "7001")
+		require.Equal(t, "Maria Elena RODRIGUEZ", entity.Name)
+		require.Equal(t, search.EntityPerson, entity.Type)
+		require.Equal(t, search.SourceUSOFAC, entity.Source)
+
+		require.NotNil(t, entity.Person)
+		require.Equal(t, []string{"La Jefa"}, entity.Person.AltNames)
+		require.Equal(t, search.GenderFemale, entity.Person.Gender)
+		require.Equal(t, "1965-01-01", entity.Person.BirthDate.Format(time.DateOnly))
+
+		expectedIDs := []search.GovernmentID{
+			{Type: search.GovernmentIDPassport, Country: "Mexico", Identifier: "G12345678"},
+			{Type: search.GovernmentIDCURP, Country: "Mexico", Identifier: "ROSM650101HDFDRR09"},
+		}
+		require.Equal(t, expectedIDs, entity.Person.GovernmentIDs)
+
+		expectedAddresses := []search.Address{
+			{Line1: "Calle Mariano Escobedo 550", City: "Mexico City", PostalCode: "11560", Country: "Mexico"},
+		}
+		require.Equal(t, expectedAddresses, entity.Addresses)
+
+		require.NotNil(t, entity.SanctionsInfo)
+		require.Equal(t, []string{"SDNTK"}, entity.SanctionsInfo.Programs)
+	})
+
+	t.Run("business", func(t *testing.T) {
+		entity := find(t, "7002")
+		require.Equal(t, search.EntityBusiness, entity.Type)
+		require.NotNil(t, entity.Business)
+		require.Equal(t, []string{"EXPORTADORA NORTENA"}, entity.Business.AltNames)
+		require.Equal(t, "2008-03-14", entity.Business.Created.Format(time.DateOnly))
+
+		expected := []search.Identifier{
+			{Name: "Registration Number", Country: "Mexico", Identifier: "RME-123456"},
+		}
+		require.Equal(t, expected, entity.Business.Identifier)
+
+		require.Equal(t, []string{"www.exportadordelnorte.mx"}, entity.Contact.Websites)
+		require.Equal(t, []search.CryptoAddress{{Currency: "XBT", Address: "1AbCdEfGhIjKlMnOpQrStUvWxYz12345"}}, entity.CryptoAddresses)
+
+		require.Equal(t, []search.HistoricalInfo{{Type: "Former Name", Value: "EXPORTADORA NORTENA"}}, entity.HistoricalInfo)
+		require.Equal(t, []search.Affiliation{{EntityName: "Maria Elena RODRIGUEZ", Type: "Owned or Controlled By"}}, entity.Affiliations)
+
+		require.Equal(t, []string{"SDNTK", "ILLICIT-DRUGS-EO14059"}, entity.SanctionsInfo.Programs)
+		require.Equal(t, "Linked to Maria Elena RODRIGUEZ.", entity.SanctionsInfo.Description)
+	})
+
+	t.Run("vessel", func(t *testing.T) {
+		entity := find(t, "7003")
+		require.Equal(t, search.EntityVessel, entity.Type)
+		require.NotNil(t, entity.Vessel)
+		require.Equal(t, "NORTHERN STAR", entity.Vessel.Name)
+		require.Equal(t, "9187629", entity.Vessel.IMONumber)
+		require.Equal(t, "3FGH7", entity.Vessel.CallSign)
+		require.Equal(t, search.VesselTypeCargo, entity.Vessel.Type)
+		require.Equal(t, "Panama", entity.Vessel.Flag)
+		require.Equal(t, 24500, entity.Vessel.Tonnage)
+	})
+
+	t.Run("aircraft", func(t *testing.T) {
+		entity := find(t, "7004")
+		require.Equal(t, search.EntityAircraft, entity.Type)
+		require.NotNil(t, entity.Aircraft)
+		require.Equal(t, "Airbus A300B4", entity.Aircraft.Model)
+		require.Equal(t, "123", entity.Aircraft.SerialNumber)
+	})
+}
+
+func TestReadAdvanced_Errors(t *testing.T) {
+	_, err := ReadAdvanced(nil)
+	require.Error(t, err)
+
+	_, err = ReadAdvanced(os.NewFile(0, "missing"))
+	require.Error(t, err)
+}
@@ -44,6 +44,31 @@ func Read(files map[string]io.ReadCloser) (*Results, error) {
 				return nil, fmt.Errorf("add.csv: %v", err)
 			}
 
+		// The consolidated (non-SDN) files share the same schema as the SDN files
+		case "cons_add.csv":
+			err := res.append(csvAddressFile(file))
+			if err != nil {
+				return nil, fmt.Errorf("cons_add.csv: %v", err)
+			}
+
+		case "cons_alt.csv":
+			err := res.append(csvAlternateIdentityFile(file))
+			if err != nil {
+				return nil, fmt.Errorf("cons_alt.csv: %v", err)
+			}
+
+		case "cons_prim.csv":
+			err := res.append(csvSDNFile(file))
+			if err != nil {
+				return nil, fmt.Errorf("cons_prim.csv: %v", err)
+			}
+
+		case "cons_comments.csv":
+			err := res.append(csvSDNCommentsFile(file))
+			if err != nil {
+				return nil, fmt.Errorf("cons_comments.csv: %v", err)
+			}
+
 		default:
 			file.Close()
 			return nil, fmt.Errorf("error: file %s does not have a handler for processing", filename)
@@ -68,6 +93,9 @@ type Results struct {
 
 	// SDNComments returns an array of OFAC Specially Designated National Comments
 	SDNComments []SDNComments `json:"sdnComments"`
+
+	// SkippedSDNs is how many malformed rows of sdn.csv or cons_prim.csv were skipped
+	SkippedSDNs int `json:"skippedSDNs"`
 }
 
 func (r *Results) append(rr *Results, err error) error {
@@ -78,6 +106,7 @@ func (r *Results) append(rr *Results, err error) error {
 	r.AlternateIdentities = append(r.AlternateIdentities, rr.AlternateIdentities...)
 	r.SDNs = append(r.SDNs, rr.SDNs...)
 	r.SDNComments = append(r.SDNComments, rr.SDNComments...)
+	r.SkippedSDNs += rr.SkippedSDNs
 	return nil
 }
 
@@ -158,6 +187,7 @@ func csvAlternateIdentityFile(f io.ReadCloser) (*Results, error) {
 func csvSDNFile(f io.ReadCloser) (*Results, error) {
 	defer f.Close()
 	var out []SDN
+	var skipped int
 
 	// Read File into a Variable
 	reader := csv.NewReader(f)
@@ -172,11 +202,13 @@ func csvSDNFile(f io.ReadCloser) (*Results, error) {
 			if errors.Is(err, csv.ErrFieldCount) ||
 				errors.Is(err, csv.ErrBareQuote) ||
 				errors.Is(err, csv.ErrQuote) {
+				skipped++
 				continue
 			}
 			return nil, err
 		}
 		if len(record) != 12 {
+			skipped++
 			continue
 		}
 		record = replaceNull(record)
@@ -195,7 +227,7 @@ func csvSDNFile(f io.ReadCloser) (*Results, error) {
 			Remarks:                record[11],
 		})
 	}
-	return &Results{SDNs: out}, nil
+	return &Results{SDNs: out, SkippedSDNs: skipped}, nil
 }
 
 func csvSDNCommentsFile(f io.ReadCloser) (*Results, error) {
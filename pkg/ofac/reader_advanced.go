@@ -0,0 +1,26 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package ofac
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadAdvanced parses an OFAC advanced XML file (SDN_ADVANCED.XML or CONS_ADVANCED.XML)
+func ReadAdvanced(fd io.ReadCloser) (*AdvancedSanctions, error) {
+	if fd == nil {
+		return nil, errors.New("OFAC advanced XML file is empty or missing")
+	}
+	defer fd.Close()
+
+	var out AdvancedSanctions
+	if err := xml.NewDecoder(fd).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding OFAC advanced XML: %w", err)
+	}
+	return &out, nil
+}
@@ -12,6 +12,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/moov-io/watchman/pkg/download"
+	"github.com/moov-io/watchman/pkg/search"
+
 	"github.com/moov-io/base/log"
 	"github.com/stretchr/testify/require"
 )
@@ -54,6 +57,32 @@ func TestOFAC__read(t *testing.T) {
 	require.Len(t, res.SDNComments, 13)
 }
 
+func TestOFAC__readConsolidated(t *testing.T) {
+	files := make(map[string]io.ReadCloser)
+	for _, fn := range []string{"cons_add.csv", "cons_alt.csv", "cons_prim.csv", "cons_comments.csv"} {
+		fd, err := os.Open(filepath.Join("testdata", fn))
+		require.NoError(t, err)
+		files[fn] = fd
+	}
+
+	res, err := Read(files)
+	require.NoError(t, err)
+	require.Len(t, res.Addresses, 1)
+	require.Len(t, res.AlternateIdentities, 2)
+	require.Len(t, res.SDNs, 2)
+	require.Len(t, res.SDNComments, 0)
+
+	entities := GroupConsolidatedIntoEntities(res.SDNs, res.Addresses, res.SDNComments, res.AlternateIdentities)
+	require.Len(t, entities, 2)
+	for _, entity := range entities {
+		require.Equal(t, search.SourceUSNonSDN, entity.Source)
+	}
+	require.Equal(t, "GAZPROM NEFT", entities[0].Name)
+	require.Equal(t, search.EntityBusiness, entities[0].Type)
+	require.Equal(t, "Ivan Petrovich NOVIKOV", entities[1].Name)
+	require.Equal(t, search.EntityPerson, entities[1].Type)
+}
+
 func TestReplaceNull(t *testing.T) {
 	ans := replaceNull(nil)
 	if ans != nil {
@@ -195,7 +224,7 @@ func TestDownload__OFAC_Spillover(t *testing.T) {
 	logger := log.NewTestLogger()
 	initialDir := filepath.Join("..", "..", "test", "testdata", "static")
 
-	files, err := Download(ctx, logger, initialDir)
+	files, err := Download(ctx, logger, initialDir, download.Options{})
 	require.NoError(t, err)
 
 	res, err := Read(files)
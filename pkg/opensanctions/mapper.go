@@ -0,0 +1,446 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opensanctions
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// ConvertEntities maps each Person, Company, Organization, Vessel and Airplane into a search.Entity.
+//
+// Address, Ownership, Family, Sanction and identity document entities are not returned themselves,
+// but are merged into the entities they refer to.
+func ConvertEntities(entities []Entity) []search.Entity[search.Value] {
+	links := newLinks(entities)
+
+	var out []search.Entity[search.Value]
+	for _, entity := range entities {
+		if !searchable(entity.Schema) {
+			continue
+		}
+		out = append(out, ToEntity(entity, links))
+	}
+	return out
+}
+
+func searchable(schema string) bool {
+	switch schema {
+	case "Person", "Company", "LegalEntity", "Organization", "PublicBody", "Vessel", "Airplane":
+		return true
+	}
+	return false
+}
+
+// links holds the entities which describe or connect other entities, keyed by the ID they refer to
+type links struct {
+	entities map[string]Entity
+
+	affiliations map[string][]search.Affiliation
+	sanctions    map[string][]Entity
+	documents    map[string][]Entity
+	owners       map[string][]string
+}
+
+func newLinks(entities []Entity) *links {
+	out := &links{
+		entities:     make(map[string]Entity, len(entities)),
+		affiliations: make(map[string][]search.Affiliation),
+		sanctions:    make(map[string][]Entity),
+		documents:    make(map[string][]Entity),
+		owners:       make(map[string][]string),
+	}
+	for _, entity := range entities {
+		out.entities[entity.ID] = entity
+	}
+
+	for _, entity := range entities {
+		props := entity.Properties
+
+		switch entity.Schema {
+		case "Ownership":
+			owner, asset := props.First("owner"), props.First("asset")
+			if owner == "" || asset == "" {
+				continue
+			}
+			details := props.First("percentage")
+			if details != "" && !strings.HasSuffix(details, "%") {
+				details += "%"
+			}
+			out.affiliations[asset] = append(out.affiliations[asset], search.Affiliation{
+				EntityName: out.name(owner),
+				Type:       "Owned By",
+				Details:    details,
+			})
+			out.affiliations[owner] = append(out.affiliations[owner], search.Affiliation{
+				EntityName: out.name(asset),
+				Type:       "Owner Of",
+				Details:    details,
+			})
+			out.owners[asset] = append(out.owners[asset], out.name(owner))
+
+		case "Family":
+			person, relative := props.First("person"), props.First("relative")
+			if person == "" || relative == "" {
+				continue
+			}
+			out.affiliations[person] = append(out.affiliations[person], search.Affiliation{
+				EntityName: out.name(relative),
+				Type:       "Family Member",
+				Details:    props.First("relationship"),
+			})
+			out.affiliations[relative] = append(out.affiliations[relative], search.Affiliation{
+				EntityName: out.name(person),
+				Type:       "Family Member",
+			})
+
+		case "Sanction":
+			for _, id := range props.Values("entity") {
+				out.sanctions[id] = append(out.sanctions[id], entity)
+			}
+
+		case "Identification", "Passport":
+			for _, id := range props.Values("holder") {
+				out.documents[id] = append(out.documents[id], entity)
+			}
+		}
+	}
+
+	return out
+}
+
+// name returns the caption of an entity, falling back to the ID when it's not in the export
+func (l *links) name(id string) string {
+	entity, exists := l.entities[id]
+	if !exists {
+		return id
+	}
+	if entity.Caption != "" {
+		return entity.Caption
+	}
+	if name := entity.Properties.First("name"); name != "" {
+		return name
+	}
+	return id
+}
+
+func ToEntity(entity Entity, links *links) search.Entity[search.Value] {
+	props := entity.Properties
+
+	out := search.Entity[search.Value]{
+		Name:       firstNonEmpty(entity.Caption, props.First("name")),
+		Source:     search.SourceOpenSanctions,
+		SourceID:   entity.ID,
+		SourceData: entity,
+	}
+	altNames := mapAltNames(out.Name, props)
+
+	switch entity.Schema {
+	case "Person":
+		out.Type = search.EntityPerson
+		out.Person = &search.Person{
+			Name:          out.Name,
+			AltNames:      altNames,
+			Gender:        mapGender(props.First("gender")),
+			BirthDate:     parseDate(props.First("birthDate")),
+			DeathDate:     parseDate(props.First("deathDate")),
+			Titles:        props.Values("title", "position"),
+			GovernmentIDs: mapGovernmentIDs(entity, links.documents[entity.ID]),
+		}
+		out.Titles = out.Person.Titles
+
+	case "Company", "LegalEntity":
+		out.Type = search.EntityBusiness
+		out.Business = &search.Business{
+			Name:       out.Name,
+			AltNames:   altNames,
+			Created:    parseDate(props.First("incorporationDate")),
+			Dissolved:  parseDate(props.First("dissolutionDate")),
+			Identifier: mapIdentifiers(props),
+		}
+
+	case "Organization", "PublicBody":
+		out.Type = search.EntityOrganization
+		out.Organization = &search.Organization{
+			Name:       out.Name,
+			AltNames:   altNames,
+			Created:    parseDate(props.First("incorporationDate")),
+			Dissolved:  parseDate(props.First("dissolutionDate")),
+			Identifier: mapIdentifiers(props),
+		}
+
+	case "Vessel":
+		out.Type = search.EntityVessel
+		out.Vessel = &search.Vessel{
+			Name:                   out.Name,
+			AltNames:               altNames,
+			IMONumber:              strings.TrimSpace(strings.TrimPrefix(props.First("imoNumber"), "IMO")),
+			Type:                   mapVesselType(props.First("type")),
+			Flag:                   strings.ToUpper(firstNonEmpty(props.First("flag"), props.First("country"))),
+			Built:                  parseDate(props.First("buildDate")),
+			Model:                  props.First("model"),
+			Tonnage:                parseNumber(props.First("tonnage")),
+			MMSI:                   props.First("mmsi"),
+			CallSign:               props.First("callSign"),
+			GrossRegisteredTonnage: parseNumber(props.First("grossRegisteredTonnage")),
+		}
+		if owners := links.owners[entity.ID]; len(owners) > 0 {
+			out.Vessel.Owner = owners[0]
+		}
+
+	case "Airplane":
+		out.Type = search.EntityAircraft
+		out.Aircraft = &search.Aircraft{
+			Name:         out.Name,
+			AltNames:     altNames,
+			Type:         mapAircraftType(props.First("type")),
+			Flag:         strings.ToUpper(firstNonEmpty(props.First("flag"), props.First("country"))),
+			Built:        parseDate(props.First("buildDate")),
+			ICAOCode:     props.First("icaoCode"),
+			Model:        props.First("model"),
+			SerialNumber: props.First("serialNumber"),
+		}
+	}
+
+	out.Contact = search.ContactInfo{
+		EmailAddresses: props.Values("email"),
+		PhoneNumbers:   props.Values("phone"),
+		Websites:       props.Values("website"),
+	}
+	out.Addresses = mapAddresses(props, links)
+	out.Affiliations = links.affiliations[entity.ID]
+	out.SanctionsInfo = mapSanctionsInfo(links.sanctions[entity.ID])
+
+	for _, name := range props.Values("previousName") {
+		out.HistoricalInfo = append(out.HistoricalInfo, search.HistoricalInfo{
+			Type:  "Former Name",
+			Value: name,
+		})
+	}
+
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func mapAltNames(name string, props Properties) []string {
+	var out []string
+	seen := map[string]bool{
+		strings.ToLower(name): true,
+	}
+	for _, alt := range props.Values("name", "alias") {
+		key := strings.ToLower(alt)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, alt)
+	}
+	return out
+}
+
+func mapGender(value string) search.Gender {
+	switch strings.ToLower(value) {
+	case "male":
+		return search.GenderMale
+	case "female":
+		return search.GenderFemale
+	}
+	return search.GenderUnknown
+}
+
+var (
+	dateFormats = []string{"2006-01-02", "2006-01", "2006"}
+)
+
+// parseDate reads FtM dates, which are ISO 8601 values with as much precision as is known
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	for _, format := range dateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return &tt
+		}
+	}
+	return nil
+}
+
+func parseNumber(value string) int {
+	n, _ := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+	return n
+}
+
+func mapVesselType(value string) search.VesselType {
+	if value == "" {
+		return ""
+	}
+	if strings.Contains(strings.ToLower(value), "cargo") {
+		return search.VesselTypeCargo
+	}
+	return search.VesselTypeUnknown
+}
+
+func mapAircraftType(value string) search.AircraftType {
+	if value == "" {
+		return ""
+	}
+	if strings.Contains(strings.ToLower(value), "cargo") {
+		return search.AircraftCargo
+	}
+	return search.AircraftTypeUnknown
+}
+
+func mapGovernmentIDs(entity Entity, documents []Entity) []search.GovernmentID {
+	props := entity.Properties
+	country := strings.ToUpper(firstNonEmpty(props.First("nationality"), props.First("country")))
+
+	var out []search.GovernmentID
+	add := func(idType search.GovernmentIDType, country, value string) {
+		if value = strings.TrimSpace(value); value == "" {
+			return
+		}
+		for _, existing := range out {
+			if existing.Identifier == value {
+				return
+			}
+		}
+		out = append(out, search.GovernmentID{
+			Type:       idType,
+			Country:    country,
+			Identifier: value,
+		})
+	}
+
+	for _, doc := range documents {
+		idType := search.GovernmentIDPersonalID
+		if doc.Schema == "Passport" || strings.Contains(strings.ToLower(doc.Properties.First("type")), "passport") {
+			idType = search.GovernmentIDPassport
+		}
+		docCountry := strings.ToUpper(firstNonEmpty(doc.Properties.First("country"), country))
+		add(idType, docCountry, doc.Properties.First("number"))
+	}
+	for _, value := range props.Values("passportNumber") {
+		add(search.GovernmentIDPassport, country, value)
+	}
+	for _, value := range props.Values("taxNumber", "innCode") {
+		add(search.GovernmentIDTax, country, value)
+	}
+	for _, value := range props.Values("idNumber") {
+		add(search.GovernmentIDPersonalID, country, value)
+	}
+	return out
+}
+
+var identifierProperties = []struct {
+	property string
+	name     string
+}{
+	{property: "registrationNumber", name: "Registration Number"},
+	{property: "taxNumber", name: "Tax ID"},
+	{property: "vatCode", name: "V.A.T. Number"},
+	{property: "leiCode", name: "LEI"},
+	{property: "innCode", name: "INN"},
+	{property: "ogrnCode", name: "OGRN"},
+	{property: "dunsCode", name: "D-U-N-S Number"},
+	{property: "swiftBic", name: "SWIFT/BIC"},
+	{property: "imoNumber", name: "IMO Number"},
+}
+
+func mapIdentifiers(props Properties) []search.Identifier {
+	country := strings.ToUpper(firstNonEmpty(props.First("jurisdiction"), props.First("country")))
+
+	var out []search.Identifier
+	for _, prop := range identifierProperties {
+		for _, value := range props.Values(prop.property) {
+			out = append(out, search.Identifier{
+				Name:       prop.name,
+				Country:    country,
+				Identifier: value,
+			})
+		}
+	}
+	return out
+}
+
+func mapAddresses(props Properties, links *links) []search.Address {
+	var out []search.Address
+	for _, id := range props.Values("addressEntity") {
+		addr, exists := links.entities[id]
+		if !exists || addr.Schema != "Address" {
+			continue
+		}
+		p := addr.Properties
+		address := search.Address{
+			Line1:      firstNonEmpty(p.First("street"), p.First("full")),
+			Line2:      p.First("street2"),
+			City:       p.First("city"),
+			PostalCode: p.First("postalCode"),
+			State:      firstNonEmpty(p.First("state"), p.First("region")),
+			Country:    strings.ToUpper(p.First("country")),
+		}
+		if lat, err := strconv.ParseFloat(p.First("latitude"), 64); err == nil {
+			address.Latitude = lat
+		}
+		if lon, err := strconv.ParseFloat(p.First("longitude"), 64); err == nil {
+			address.Longitude = lon
+		}
+		out = append(out, address)
+	}
+
+	// Fallback to the full text addresses when no structured Address entities are linked
+	if len(out) == 0 {
+		for _, value := range props.Values("address") {
+			out = append(out, search.Address{
+				Line1: value,
+			})
+		}
+	}
+	return out
+}
+
+func mapSanctionsInfo(sanctions []Entity) *search.SanctionsInfo {
+	if len(sanctions) == 0 {
+		return nil
+	}
+
+	info := &search.SanctionsInfo{}
+	var reasons []string
+	for _, sanction := range sanctions {
+		// prefer the short program ID when it exists
+		program := firstNonEmpty(sanction.Properties.First("programId"), sanction.Properties.First("program"))
+		if program != "" && !contains(info.Programs, program) {
+			info.Programs = append(info.Programs, program)
+		}
+		for _, reason := range sanction.Properties.Values("reason") {
+			if !contains(reasons, reason) {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	info.Description = strings.Join(reasons, " ")
+	return info
+}
+
+func contains(values []string, needle string) bool {
+	for _, v := range values {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
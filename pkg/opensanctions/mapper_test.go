@@ -0,0 +1,125 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opensanctions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertEntities(t *testing.T) {
+	entities, err := ReadDir("testdata")
+	require.NoError(t, err)
+
+	found := ConvertEntities(entities)
+	require.Len(t, found, 6)
+
+	find := func(t *testing.T, id string) search.Entity[search.Value] {
+		t.Helper()
+		for _, entity := range found {
+			if entity.SourceID == id {
+				return entity
+			}
+		}
+		t.Fatalf("entity %s not found", id)
+		return search.Entity[search.Value]{}
+	}
+
+	t.Run("person", func(t *testing.T) {
+		entity := find(t, "NK-abc123")
+		require.Equal(t, "Ivan Petrovich Novikov", entity.Name)
+		require.Equal(t, search.EntityPerson, entity.Type)
+		require.Equal(t, search.SourceOpenSanctions, entity.Source)
+
+		require.NotNil(t, entity.Person)
+		require.Equal(t, []string{"Ivan Novikov", "Иван Петрович Новиков"}, entity.Person.AltNames)
+		require.Equal(t, search.GenderMale, entity.Person.Gender)
+		require.Equal(t, "1970-03-12", entity.Person.BirthDate.Format(time.DateOnly))
+		require.Equal(t, []string{"Member of the State Duma"}, entity.Person.Titles)
+
+		expectedIDs := []search.GovernmentID{
+			{Type: search.GovernmentIDPassport, Country: "RU", Identifier: "751234567"},
+			{Type: search.GovernmentIDPassport, Country: "RU", Identifier: "720012345"},
+		}
+		require.Equal(t, expectedIDs, entity.Person.GovernmentIDs)
+
+		expectedAddresses := []search.Address{
+			{Line1: "Tverskaya 7", City: "Moscow", PostalCode: "125009", Country: "RU"},
+		}
+		require.Equal(t, expectedAddresses, entity.Addresses)
+		require.Equal(t, []string{"ivan@example.ru"}, entity.Contact.EmailAddresses)
+
+		expectedAffiliations := []search.Affiliation{
+			{EntityName: "Novikov Shipping LLC", Type: "Owner Of", Details: "51%"},
+			{EntityName: "Elena Novikova", Type: "Family Member", Details: "Spouse"},
+		}
+		require.Equal(t, expectedAffiliations, entity.Affiliations)
+
+		require.NotNil(t, entity.SanctionsInfo)
+		require.Equal(t, []string{"RUSSIA-EO14024"}, entity.SanctionsInfo.Programs)
+		require.Equal(t, "Member of the State Duma.", entity.SanctionsInfo.Description)
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		entity := find(t, "NK-def456")
+		require.Equal(t, search.GenderFemale, entity.Person.Gender)
+		require.Equal(t, []search.Affiliation{{EntityName: "Ivan Petrovich Novikov", Type: "Family Member"}}, entity.Affiliations)
+		require.Nil(t, entity.SanctionsInfo)
+	})
+
+	t.Run("company", func(t *testing.T) {
+		entity := find(t, "NK-ghi789")
+		require.Equal(t, search.EntityBusiness, entity.Type)
+		require.NotNil(t, entity.Business)
+		require.Equal(t, []string{"OOO Novikov Shipping"}, entity.Business.AltNames)
+		require.Equal(t, "2008-06-01", entity.Business.Created.Format(time.DateOnly))
+
+		expected := []search.Identifier{
+			{Name: "Registration Number", Country: "RU", Identifier: "1027700132195"},
+			{Name: "INN", Country: "RU", Identifier: "7708004767"},
+		}
+		require.Equal(t, expected, entity.Business.Identifier)
+		require.Equal(t, []search.HistoricalInfo{{Type: "Former Name", Value: "Baltic Freight LLC"}}, entity.HistoricalInfo)
+
+		expectedAffiliations := []search.Affiliation{
+			{EntityName: "Ivan Petrovich Novikov", Type: "Owned By", Details: "51%"},
+			{EntityName: "BALTIC STAR", Type: "Owner Of"},
+		}
+		require.Equal(t, expectedAffiliations, entity.Affiliations)
+	})
+
+	t.Run("organization", func(t *testing.T) {
+		entity := find(t, "NK-org001")
+		require.Equal(t, search.EntityOrganization, entity.Type)
+		require.NotNil(t, entity.Organization)
+		require.Equal(t, []search.Address{{Line1: "Nevsky Prospekt 1, St. Petersburg"}}, entity.Addresses)
+	})
+
+	t.Run("vessel", func(t *testing.T) {
+		entity := find(t, "NK-ves001")
+		require.Equal(t, search.EntityVessel, entity.Type)
+		require.NotNil(t, entity.Vessel)
+		require.Equal(t, "9187629", entity.Vessel.IMONumber)
+		require.Equal(t, "273456780", entity.Vessel.MMSI)
+		require.Equal(t, "UBCD7", entity.Vessel.CallSign)
+		require.Equal(t, search.VesselTypeCargo, entity.Vessel.Type)
+		require.Equal(t, 4250, entity.Vessel.GrossRegisteredTonnage)
+		require.Equal(t, "1999", entity.Vessel.Built.Format("2006"))
+		require.Equal(t, "Novikov Shipping LLC", entity.Vessel.Owner)
+	})
+
+	t.Run("aircraft", func(t *testing.T) {
+		entity := find(t, "NK-air001")
+		require.Equal(t, search.EntityAircraft, entity.Type)
+		require.NotNil(t, entity.Aircraft)
+		require.Equal(t, "Gulfstream G650", entity.Aircraft.Model)
+		require.Equal(t, "6123", entity.Aircraft.SerialNumber)
+		require.Equal(t, "RU", entity.Aircraft.Flag)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opensanctions
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Entity is a single FollowTheMoney (FtM) entity as exported by OpenSanctions.
+//
+// Each line of an entities.ftm.json export is one Entity. Links between entities (ownership,
+// family, sanctions, addresses) are entities themselves which refer to others by ID.
+//
+// See: https://www.opensanctions.org/reference/
+type Entity struct {
+	ID         string     `json:"id"`
+	Schema     string     `json:"schema"`
+	Caption    string     `json:"caption"`
+	Properties Properties `json:"properties"`
+
+	Datasets   []string `json:"datasets"`
+	Referents  []string `json:"referents"`
+	Target     bool     `json:"target"`
+	FirstSeen  string   `json:"first_seen"`
+	LastSeen   string   `json:"last_seen"`
+	LastChange string   `json:"last_change"`
+}
+
+// Properties holds the values of each FtM property. Every property is multi-valued.
+//
+// Nested exports include the referenced entity instead of its ID, in which case only the ID is kept.
+type Properties map[string][]string
+
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	var raw map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	out := make(Properties, len(raw))
+	for name, values := range raw {
+		for _, value := range values {
+			var str string
+			if err := json.Unmarshal(value, &str); err == nil {
+				out[name] = append(out[name], str)
+				continue
+			}
+
+			var nested struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(value, &nested); err == nil && nested.ID != "" {
+				out[name] = append(out[name], nested.ID)
+			}
+		}
+	}
+	*p = out
+	return nil
+}
+
+// First returns the first non-empty value of a property
+func (p Properties) First(name string) string {
+	for _, value := range p[name] {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Values returns each non-empty value of the given properties
+func (p Properties) Values(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		for _, value := range p[name] {
+			if value = strings.TrimSpace(value); value != "" {
+				out = append(out, value)
+			}
+		}
+	}
+	return out
+}
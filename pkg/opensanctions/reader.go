@@ -0,0 +1,94 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opensanctions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReadDir reads every FtM entity file (.json, .ndjson or .jsonl) within dir. A path to a
+// single file is also accepted.
+func ReadDir(dir string) ([]Entity, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opensanctions: %w", err)
+	}
+	if !info.IsDir() {
+		fd, err := os.Open(dir)
+		if err != nil {
+			return nil, fmt.Errorf("opensanctions: %w", err)
+		}
+		return ReadFile(fd)
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opensanctions: reading %s: %w", dir, err)
+	}
+
+	var filenames []string
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".ndjson", ".jsonl":
+			filenames = append(filenames, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(filenames)
+
+	var out []Entity
+	for _, filename := range filenames {
+		fd, err := os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("opensanctions: %w", err)
+		}
+		entities, err := ReadFile(fd)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(filename), err)
+		}
+		out = append(out, entities...)
+	}
+	return out, nil
+}
+
+func ReadFile(fd io.ReadCloser) ([]Entity, error) {
+	if fd == nil {
+		return nil, errors.New("opensanctions file is empty or missing")
+	}
+	defer fd.Close()
+
+	return Parse(fd)
+}
+
+// Parse reads newline delimited FtM entities
+func Parse(r io.Reader) ([]Entity, error) {
+	var out []Entity
+
+	dec := json.NewDecoder(r)
+	for {
+		var entity Entity
+		err := dec.Decode(&entity)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decoding opensanctions entity %d: %w", len(out)+1, err)
+		}
+		if entity.ID == "" || entity.Schema == "" {
+			continue
+		}
+		out = append(out, entity)
+	}
+	return out, nil
+}
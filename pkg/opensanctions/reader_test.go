@@ -0,0 +1,44 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package opensanctions
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDir(t *testing.T) {
+	entities, err := ReadDir("testdata")
+	require.NoError(t, err)
+	require.Len(t, entities, 12)
+
+	person := entities[0]
+	require.Equal(t, "NK-abc123", person.ID)
+	require.Equal(t, "Person", person.Schema)
+	require.Equal(t, []string{"us_ofac_sdn", "ru_rupep"}, person.Datasets)
+	require.Equal(t, "Ivan Novikov", person.Properties.Values("alias")[0])
+
+	// nested entities are reduced to their ID
+	require.Equal(t, "NK-ghi789", entities[8].Properties.First("owner"))
+
+	single, err := ReadDir(filepath.Join("testdata", "entities.ftm.json"))
+	require.NoError(t, err)
+	require.Len(t, single, len(entities))
+
+	_, err = ReadDir(filepath.Join("testdata", "missing"))
+	require.Error(t, err)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"id": "a", "schema": "Person"}` + "\n" + `{"id": `))
+	require.ErrorContains(t, err, "entity 2")
+
+	entities, err := Parse(strings.NewReader(`{"schema": "Person"}`))
+	require.NoError(t, err)
+	require.Empty(t, entities)
+}
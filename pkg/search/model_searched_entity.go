@@ -4,4 +4,22 @@ type SearchedEntity[T any] struct {
 	Entity[T]
 
 	Match float64 `json:"match"`
+
+	// ClusterID groups records from different lists which describe the same party.
+	// It's empty when the entity isn't linked to any other records.
+	ClusterID string `json:"clusterID,omitempty"`
+
+	// Linked are the other records in the entity's cluster
+	Linked []LinkedEntity `json:"linked,omitempty"`
+
+	// Explanation is how Match was calculated, which is only included when requested
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
+
+// LinkedEntity is a record from another list which describes the same party
+type LinkedEntity struct {
+	Name     string     `json:"name"`
+	Type     EntityType `json:"entityType"`
+	Source   SourceList `json:"sourceList"`
+	SourceID string     `json:"sourceID"`
 }
@@ -53,8 +53,21 @@ var (
 
 	SourceEUCSL  SourceList = "eu_csl"
 	SourceUKCSL  SourceList = "uk_csl"
+	SourceUNCSL  SourceList = "un_csl"
 	SourceUSCSL  SourceList = "us_csl"
 	SourceUSOFAC SourceList = "us_ofac"
+
+	// SourceUSNonSDN is OFAC's Consolidated (non-SDN) list
+	SourceUSNonSDN SourceList = "us_non_sdn"
+
+	// SourceUKSanctionsList is the UK Sanctions List, which uses its own IDs rather than the UK CSL's group IDs
+	SourceUKSanctionsList SourceList = "uk_sanctions_list"
+
+	// SourceUSDPL is the BIS Denied Persons List
+	SourceUSDPL SourceList = "us_dpl"
+
+	// SourceOpenSanctions is any FollowTheMoney dataset exported by OpenSanctions
+	SourceOpenSanctions SourceList = "opensanctions"
 )
 
 type Person struct {
@@ -65,6 +78,9 @@ type Person struct {
 	DeathDate *time.Time `json:"deathDate"`
 	Titles    []string   `json:"titles"`
 
+	// Nationalities are the countries the person is a citizen of, as written by the source list
+	Nationalities []string `json:"nationalities"`
+
 	GovernmentIDs []GovernmentID `json:"governmentIDs"`
 }
 
@@ -224,6 +240,9 @@ type Affiliation struct {
 }
 
 type SanctionsInfo struct {
+	// List is the list within the source the entity was published on, e.g. "EL" for the US CSL's Entity List
+	List string `json:"list,omitempty"`
+
 	Programs    []string `json:"programs"`    // e.g., "SDGT", "IRGC"
 	Secondary   bool     `json:"secondary"`   // Subject to secondary sanctions
 	Description string   `json:"description"` // Additional details
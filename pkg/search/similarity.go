@@ -25,25 +25,36 @@ func Similarity[Q any, I any](query Entity[Q], index Entity[I]) float64 {
 
 // DebugSimilarity does the same as Similarity, but logs debug info to w.
 func DebugSimilarity[Q any, I any](w io.Writer, query Entity[Q], index Entity[I]) float64 {
+	return similarity(w, nil, query, index)
+}
+
+// similarity logs debug info to w and records each step in explain, both of which can be nil.
+func similarity[Q any, I any](w io.Writer, explain *Explanation, query Entity[Q], index Entity[I]) float64 {
 	var pieces []scorePiece
 
 	// Critical identifiers (highest weight)
 	exactIdentifiers := compareExactIdentifiers(w, query, index, criticalIdWeight)
+	explain.addPieces(exactIdentifiers)
 	if exactIdentifiers.matched && exactIdentifiers.fieldsCompared > 0 {
+		explain.decidedBy(exactIdentifiers)
 		if math.IsNaN(exactIdentifiers.score) {
 			return 0.0
 		}
 		return exactIdentifiers.score
 	}
 	exactCryptoAddresses := compareExactCryptoAddresses(w, query, index, criticalIdWeight)
+	explain.addPieces(exactCryptoAddresses)
 	if exactCryptoAddresses.matched && exactCryptoAddresses.fieldsCompared > 0 {
+		explain.decidedBy(exactCryptoAddresses)
 		if math.IsNaN(exactCryptoAddresses.score) {
 			return 0.0
 		}
 		return exactCryptoAddresses.score
 	}
 	exactGovernmentIDs := compareExactGovernmentIDs(w, query, index, criticalIdWeight)
+	explain.addPieces(exactGovernmentIDs)
 	if exactGovernmentIDs.matched && exactGovernmentIDs.fieldsCompared > 0 {
+		explain.decidedBy(exactGovernmentIDs)
 		if math.IsNaN(exactGovernmentIDs.score) {
 			return 0.0
 		}
@@ -82,7 +93,9 @@ func DebugSimilarity[Q any, I any](w io.Writer, query Entity[Q], index Entity[I]
 		debug(w, "supporting into: %#v\n", pieces[7])
 	}
 
-	finalScore := calculateFinalScore(w, pieces, query, index)
+	explain.addPieces(pieces[3:]...)
+
+	finalScore := calculateFinalScore(w, explain, pieces, query, index)
 	if math.IsNaN(finalScore) {
 		return 0.0
 	}
@@ -101,6 +114,10 @@ type scorePiece struct {
 	exact          bool    // whether it's an exact match
 	fieldsCompared int     // how many fields were actually compared
 	pieceType      string  // e.g. "identifiers", "name", etc.
+
+	// matchedName is the index entity's name which scored best, for name pieces
+	matchedName     string
+	matchedNameKind string
 }
 
 func boolToScore(b bool) float64 {
@@ -153,8 +170,12 @@ type entityFields struct {
 	hasCritical bool
 }
 
-func calculateFinalScore[Q any, I any](w io.Writer, pieces []scorePiece, query Entity[Q], index Entity[I]) float64 {
-	if len(pieces) == 0 || query.Type != index.Type {
+func calculateFinalScore[Q any, I any](w io.Writer, explain *Explanation, pieces []scorePiece, query Entity[Q], index Entity[I]) float64 {
+	if len(pieces) == 0 {
+		return 0
+	}
+	if query.Type != index.Type {
+		explain.adjust(AdjustmentTypeMismatch, 0, 0)
 		return 0
 	}
 
@@ -165,8 +186,10 @@ func calculateFinalScore[Q any, I any](w io.Writer, pieces []scorePiece, query E
 	// Calculate base score with weighted importance
 	baseScore := calculateBaseScore(pieces, fields)
 
+	explain.setScores(coverage, baseScore)
+
 	// Apply coverage penalties
-	finalScore := applyPenaltiesAndBonuses(baseScore, coverage, fields, query.Type == index.Type)
+	finalScore := applyPenaltiesAndBonuses(explain, baseScore, coverage, fields, query.Type == index.Type)
 
 	if w != nil {
 		debug(w, "calculateFinalScore: fields=%#v  coverage=%#v ", fields, coverage)
@@ -257,25 +280,29 @@ type coverage struct {
 	criticalRatio float64
 }
 
-func applyPenaltiesAndBonuses(baseScore float64, cov coverage, fields entityFields, sameType bool) float64 {
+func applyPenaltiesAndBonuses(explain *Explanation, baseScore float64, cov coverage, fields entityFields, sameType bool) float64 {
 	score := baseScore
 
 	// Apply coverage penalties
 	if cov.ratio < minCoverageThreshold {
 		score *= 0.98 // Significant penalty for low overall coverage
+		explain.adjust(AdjustmentLowCoverage, 0.98, score)
 	}
 	if cov.criticalRatio < criticalCovThreshold {
 		score *= 0.95 // Penalty for missing critical fields
+		explain.adjust(AdjustmentLowCriticalCoverage, 0.95, score)
 	}
 
 	// Apply perfect match bonus
 	if fields.hasName && fields.hasID && fields.hasCritical && cov.ratio > 0.95 && score > highConfidenceThreshold {
 		score = math.Min(1.0, score*perfectMatchBoost)
+		explain.adjust(AdjustmentPerfectMatch, perfectMatchBoost, score)
 	}
 
 	// Handle type mismatches
 	if !sameType {
 		score = math.Min(score, typeMismatchScore)
+		explain.adjust(AdjustmentTypeMismatch, 0, score)
 	}
 
 	return score
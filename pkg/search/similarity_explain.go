@@ -0,0 +1,148 @@
+package search
+
+import (
+	"math"
+)
+
+// Explanation is a breakdown of how a match score was calculated, which can be used to justify a disposition.
+type Explanation struct {
+	// Pieces are the result of each comparison between the query and index entity
+	Pieces []ScorePiece `json:"pieces"`
+
+	// DecidedBy is set when one piece, such as an exact identifier match, decided the score on its own.
+	// Coverage and adjustments aren't calculated in that case.
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// Coverage is the ratio of the index entity's fields which were compared
+	Coverage float64 `json:"coverage"`
+
+	// CriticalCoverage is the ratio of required fields which were compared
+	CriticalCoverage float64 `json:"criticalCoverage"`
+
+	// BaseScore is the weighted score of every piece, before Adjustments
+	BaseScore float64 `json:"baseScore"`
+
+	// Adjustments are the penalties and bonuses applied to BaseScore, in order
+	Adjustments []ScoreAdjustment `json:"adjustments"`
+
+	FinalScore float64 `json:"finalScore"`
+
+	// BestName is the index entity's name which produced the best name score
+	BestName *NameMatch `json:"bestName,omitempty"`
+}
+
+// ScorePiece is the result of one comparison, such as names or addresses
+type ScorePiece struct {
+	Type           string  `json:"type"`
+	Score          float64 `json:"score"`
+	Weight         float64 `json:"weight"`
+	Matched        bool    `json:"matched"`
+	Required       bool    `json:"required"`
+	Exact          bool    `json:"exact"`
+	FieldsCompared int     `json:"fieldsCompared"`
+}
+
+// ScoreAdjustment is a penalty or bonus applied to the base score
+type ScoreAdjustment struct {
+	Reason string `json:"reason"`
+
+	// Factor is what the score was multiplied by. It's zero when the score was capped instead.
+	Factor float64 `json:"factor,omitempty"`
+
+	// Score is the result after this adjustment
+	Score float64 `json:"score"`
+}
+
+// Reasons for a ScoreAdjustment
+const (
+	AdjustmentLowCoverage         = "low_coverage"
+	AdjustmentLowCriticalCoverage = "low_critical_coverage"
+	AdjustmentPerfectMatch        = "perfect_match"
+	AdjustmentTypeMismatch        = "type_mismatch"
+)
+
+// NameMatch is which of the index entity's names was compared
+type NameMatch struct {
+	Name  string  `json:"name"`
+	Kind  string  `json:"kind"` // primary, alt or historical
+	Score float64 `json:"score"`
+}
+
+// Kinds of NameMatch
+const (
+	NameKindPrimary    = "primary"
+	NameKindAlt        = "alt"
+	NameKindHistorical = "historical"
+)
+
+// ExplainSimilarity does the same as Similarity and also returns how the score was calculated.
+func ExplainSimilarity[Q any, I any](query Entity[Q], index Entity[I]) (float64, *Explanation) {
+	explain := &Explanation{
+		Pieces:      []ScorePiece{},
+		Adjustments: []ScoreAdjustment{},
+	}
+	score := similarity(nil, explain, query, index)
+	explain.FinalScore = score
+	return score, explain
+}
+
+// The methods below do nothing on a nil *Explanation, so scoring only pays for explanations when asked.
+
+func (e *Explanation) addPieces(pieces ...scorePiece) {
+	if e == nil {
+		return
+	}
+	for _, p := range pieces {
+		e.Pieces = append(e.Pieces, ScorePiece{
+			Type:           p.pieceType,
+			Score:          finite(p.score),
+			Weight:         p.weight,
+			Matched:        p.matched,
+			Required:       p.required,
+			Exact:          p.exact,
+			FieldsCompared: p.fieldsCompared,
+		})
+		if p.pieceType == "name" && p.matchedName != "" {
+			e.BestName = &NameMatch{
+				Name:  p.matchedName,
+				Kind:  p.matchedNameKind,
+				Score: finite(p.score),
+			}
+		}
+	}
+}
+
+func (e *Explanation) decidedBy(piece scorePiece) {
+	if e == nil {
+		return
+	}
+	e.DecidedBy = piece.pieceType
+}
+
+func (e *Explanation) setScores(cov coverage, baseScore float64) {
+	if e == nil {
+		return
+	}
+	e.Coverage = finite(cov.ratio)
+	e.CriticalCoverage = finite(cov.criticalRatio)
+	e.BaseScore = finite(baseScore)
+}
+
+func (e *Explanation) adjust(reason string, factor, score float64) {
+	if e == nil {
+		return
+	}
+	e.Adjustments = append(e.Adjustments, ScoreAdjustment{
+		Reason: reason,
+		Factor: factor,
+		Score:  finite(score),
+	})
+}
+
+// finite replaces NaN and infinite values, which can't be encoded as JSON
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainSimilarity(t *testing.T) {
+	query := Entity[any]{
+		Name:   "Ivan Petrov",
+		Type:   EntityPerson,
+		Person: &Person{Name: "Ivan Petrov"},
+	}
+	index := Entity[any]{
+		Name:   "John Smith",
+		Type:   EntityPerson,
+		Source: SourceUSOFAC,
+		Person: &Person{
+			Name:     "John Smith",
+			AltNames: []string{"Ivan Petrov"},
+		},
+	}
+
+	score, explain := ExplainSimilarity(query, index)
+	require.InDelta(t, Similarity(query, index), score, 0.0001)
+	require.Equal(t, score, explain.FinalScore)
+	require.Empty(t, explain.DecidedBy)
+	require.Len(t, explain.Pieces, 8)
+	require.Equal(t, "name", explain.Pieces[3].Type)
+	require.True(t, explain.Pieces[3].Matched)
+	require.Equal(t, 1, explain.Pieces[3].FieldsCompared)
+	require.Equal(t, &NameMatch{Name: "Ivan Petrov", Kind: NameKindAlt, Score: 1.0}, explain.BestName)
+	require.Greater(t, explain.BaseScore, 0.0)
+
+	// every adjustment leads to the final score
+	if n := len(explain.Adjustments); n > 0 {
+		require.InDelta(t, score, explain.Adjustments[n-1].Score, 0.0001)
+	} else {
+		require.InDelta(t, score, explain.BaseScore, 0.0001)
+	}
+
+	// explanations are always valid JSON
+	_, err := json.Marshal(explain)
+	require.NoError(t, err)
+}
+
+func TestExplainSimilarity_HistoricalName(t *testing.T) {
+	query := Entity[any]{
+		Name:     "Tidewater Trading",
+		Type:     EntityBusiness,
+		Business: &Business{Name: "Tidewater Trading"},
+	}
+	index := Entity[any]{
+		Name:     "Acme Holdings",
+		Type:     EntityBusiness,
+		Business: &Business{Name: "Acme Holdings"},
+		HistoricalInfo: []HistoricalInfo{
+			{Type: "Former Name", Value: "Tidewater Trading"},
+		},
+	}
+
+	_, explain := ExplainSimilarity(query, index)
+	require.NotNil(t, explain.BestName)
+	require.Equal(t, "Tidewater Trading", explain.BestName.Name)
+	require.Equal(t, NameKindHistorical, explain.BestName.Kind)
+	require.InDelta(t, 0.95, explain.BestName.Score, 0.0001)
+}
+
+func TestExplainSimilarity_Decided(t *testing.T) {
+	passport := GovernmentID{Type: GovernmentIDPassport, Country: "US", Identifier: "X1234567"}
+	query := Entity[any]{
+		Name:   "John Doe",
+		Type:   EntityPerson,
+		Person: &Person{Name: "John Doe", GovernmentIDs: []GovernmentID{passport}},
+	}
+	index := Entity[any]{
+		Name:   "Jonathan Doe",
+		Type:   EntityPerson,
+		Person: &Person{Name: "Jonathan Doe", GovernmentIDs: []GovernmentID{passport}},
+	}
+
+	score, explain := ExplainSimilarity(query, index)
+	require.Equal(t, 1.0, score)
+	require.Equal(t, "identifiers", explain.DecidedBy)
+	require.Len(t, explain.Pieces, 1)
+	require.True(t, explain.Pieces[0].Exact)
+
+	// entities of different types can't match
+	index.Type = EntityBusiness
+	score, explain = ExplainSimilarity(query, index)
+	require.Zero(t, score)
+	require.Equal(t, []ScoreAdjustment{{Reason: AdjustmentTypeMismatch}}, explain.Adjustments)
+}
@@ -29,6 +29,9 @@ type nameMatch struct {
 	totalTerms    int
 	isExact       bool
 	isHistorical  bool
+
+	name string // the index name which was compared
+	kind string // primary, alt or historical
 }
 
 func compareName[Q any, I any](w io.Writer, query Entity[Q], index Entity[I], weight float64) scorePiece {
@@ -43,13 +46,15 @@ func compareName[Q any, I any](w io.Writer, query Entity[Q], index Entity[I], we
 	// Exact match fast path
 	if qName == iName {
 		return scorePiece{
-			score:          1.0,
-			weight:         weight,
-			matched:        true,
-			required:       true,
-			exact:          true,
-			fieldsCompared: 1,
-			pieceType:      "name",
+			score:           1.0,
+			weight:          weight,
+			matched:         true,
+			required:        true,
+			exact:           true,
+			fieldsCompared:  1,
+			pieceType:       "name",
+			matchedName:     index.Name,
+			matchedNameKind: NameKindPrimary,
 		}
 	}
 
@@ -61,11 +66,13 @@ func compareName[Q any, I any](w io.Writer, query Entity[Q], index Entity[I], we
 
 	// Check primary name
 	bestMatch := compareNameTerms(qTerms, iName)
+	bestMatch.name, bestMatch.kind = index.Name, NameKindPrimary
 
 	// Check alternate names for persons
 	if query.Person != nil && index.Person != nil {
 		for _, altName := range index.Person.AltNames {
 			altMatch := compareNameTerms(qTerms, normalizeName(altName))
+			altMatch.name, altMatch.kind = altName, NameKindAlt
 			if altMatch.score > bestMatch.score {
 				bestMatch = altMatch
 			}
@@ -78,6 +85,7 @@ func compareName[Q any, I any](w io.Writer, query Entity[Q], index Entity[I], we
 			histMatch := compareNameTerms(qTerms, normalizeName(hist.Value))
 			histMatch.score *= 0.95 // Apply penalty for historical names
 			histMatch.isHistorical = true
+			histMatch.name, histMatch.kind = hist.Value, NameKindHistorical
 			if histMatch.score > bestMatch.score {
 				bestMatch = histMatch
 			}
@@ -88,13 +96,15 @@ func compareName[Q any, I any](w io.Writer, query Entity[Q], index Entity[I], we
 	finalScore := adjustScoreBasedOnQuality(bestMatch, len(qTerms))
 
 	return scorePiece{
-		score:          finalScore,
-		weight:         weight,
-		matched:        isHighConfidenceMatch(bestMatch, finalScore),
-		required:       true,
-		exact:          finalScore > exactMatchThreshold,
-		fieldsCompared: 1,
-		pieceType:      "name",
+		score:           finalScore,
+		weight:          weight,
+		matched:         isHighConfidenceMatch(bestMatch, finalScore),
+		required:        true,
+		exact:           finalScore > exactMatchThreshold,
+		fieldsCompared:  1,
+		pieceType:       "name",
+		matchedName:     bestMatch.name,
+		matchedNameKind: bestMatch.kind,
 	}
 }
 
@@ -0,0 +1,102 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+// Package sources lets Go programs add their own lists to Watchman's refreshes.
+//
+// A Source is registered once, typically from an init function, and is then downloaded,
+// parsed and validated on every refresh alongside the built-in lists. Registered sources
+// are configured like any other list with their Name, such as in DisabledLists. Types used
+// for an entity's SourceData are registered with RegisterType.
+package sources
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/download"
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/moov-io/base/log"
+)
+
+// Source is a list of entities which Watchman downloads and searches
+type Source interface {
+	// Name identifies the list in configuration, metrics and /v2/lists. For example "us_ofac".
+	Name() string
+
+	// Files are the names of the files which make up the list. A list's LocalFile and
+	// DownloadURL settings replace where each file is read from. The refresh fails when any
+	// of them are missing, while a source without Files is skipped when none are returned.
+	Files() []string
+
+	// Download returns each file, keyed by its name. Files within initialDir
+	// should be read instead of downloaded. Files which are downloaded should use opts,
+	// which carry the list's HTTP client, headers, retries and cache directory.
+	Download(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error)
+
+	// Parse reads the downloaded files into entities
+	Parse(files map[string]io.ReadCloser) (List, error)
+
+	// Validate checks a parsed list before it replaces the list's previous entities.
+	// The previous entities are kept when an error is returned.
+	Validate(list List) error
+}
+
+// List is the parsed contents of a Source
+type List struct {
+	Source   search.SourceList
+	Entities []search.Entity[search.Value]
+
+	// Records is how many records were read from the files, including malformed ones which
+	// were skipped, when the source can tell. It's compared against Entities by the MinParseRatio refresh guard.
+	Records int
+
+	// PublishedAt is when the publisher generated the data, for files which contain it
+	PublishedAt *time.Time
+}
+
+var (
+	mu         sync.Mutex
+	registered []Source
+)
+
+// Register adds a source to every refresh. It panics when source is nil, has no Name,
+// or has the same Name as a source which is already registered.
+func Register(source Source) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if source == nil {
+		panic("sources: Register source is nil")
+	}
+	name := source.Name()
+	if name == "" {
+		panic("sources: Register source has no name")
+	}
+	for _, existing := range registered {
+		if existing.Name() == name {
+			panic(fmt.Sprintf("sources: Register called twice for %s", name))
+		}
+	}
+	registered = append(registered, source)
+}
+
+// RegisterType records the type of value so entities whose SourceData has that type can be saved in
+// snapshots. Sources which set SourceData to their own types should register each of them alongside
+// Register. Like gob.Register, it panics when two different types are registered under the same name.
+func RegisterType(value any) {
+	gob.Register(value)
+}
+
+// Registered returns each registered source in the order they were registered
+func Registered() []Source {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]Source(nil), registered...)
+}
@@ -0,0 +1,50 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package sources
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/moov-io/watchman/pkg/download"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+type namedSource string
+
+func (s namedSource) Name() string    { return string(s) }
+func (s namedSource) Files() []string { return nil }
+
+func (s namedSource) Download(ctx context.Context, logger log.Logger, initialDir string, opts download.Options) (map[string]io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (s namedSource) Parse(files map[string]io.ReadCloser) (List, error) {
+	return List{}, nil
+}
+
+func (s namedSource) Validate(list List) error {
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(namedSource("test_first"))
+	Register(namedSource("test_second"))
+
+	var names []string
+	for _, source := range Registered() {
+		names = append(names, source.Name())
+	}
+	require.Equal(t, []string{"test_first", "test_second"}, names)
+
+	require.PanicsWithValue(t, "sources: Register called twice for test_first", func() {
+		Register(namedSource("test_first"))
+	})
+	require.Panics(t, func() { Register(nil) })
+	require.Panics(t, func() { Register(namedSource("")) })
+}
@@ -0,0 +1,48 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moov-io/watchman/pkg/download"
+
+	"github.com/moov-io/base/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	files, err := Download(context.Background(), log.NewNopLogger(), "", download.Options{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	list, err := ReadFile(files["consolidated.xml"])
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Individuals)
+	require.NotEmpty(t, list.Entities)
+}
+
+func TestDownload_initialDir(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "consolidated.xml"), []byte("file=consolidated.xml"), 0600)
+	require.NoError(t, err)
+
+	files, err := Download(context.Background(), log.NewNopLogger(), dir, download.Options{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	bs, err := io.ReadAll(files["consolidated.xml"])
+	require.NoError(t, err)
+	require.Equal(t, "file=consolidated.xml", string(bs))
+}
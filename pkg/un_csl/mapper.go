@@ -0,0 +1,239 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"cmp"
+	"strings"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+)
+
+// ConvertSanctionsData maps each individual and entity from the UN Consolidated List into a search.Entity
+func ConvertSanctionsData(list *ConsolidatedList) []search.Entity[search.Value] {
+	if list == nil {
+		return nil
+	}
+
+	out := make([]search.Entity[search.Value], 0, len(list.Individuals)+len(list.Entities))
+	for _, record := range list.Individuals {
+		out = append(out, IndividualToEntity(record))
+	}
+	for _, record := range list.Entities {
+		out = append(out, EntityToEntity(record))
+	}
+	return out
+}
+
+func IndividualToEntity(record Individual) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Name:       joinNames(record.FirstName, record.SecondName, record.ThirdName, record.FourthName),
+		Type:       search.EntityPerson,
+		Source:     search.SourceUNCSL,
+		SourceID:   cmp.Or(strings.TrimSpace(record.ReferenceNumber), record.DataID),
+		SourceData: record,
+	}
+
+	var titles []string
+	titles = append(titles, record.Titles...)
+	titles = append(titles, record.Designations...)
+
+	out.Person = &search.Person{
+		Name:          out.Name,
+		AltNames:      mapAliases(record.Aliases, record.NameOriginalScript),
+		Gender:        mapGender(record.Gender),
+		BirthDate:     mapBirthDate(record.DatesOfBirth),
+		Titles:        titles,
+		GovernmentIDs: mapGovernmentIDs(record.Documents),
+	}
+	out.Titles = titles
+
+	out.Addresses = mapAddresses(record.Addresses)
+	out.SanctionsInfo = mapSanctionsInfo(record.UNListType, record.Comments)
+	out.HistoricalInfo = mapHistoricalInfo(record.ListedOn, record.LastDayUpdated)
+
+	return out
+}
+
+func EntityToEntity(record Entity) search.Entity[search.Value] {
+	out := search.Entity[search.Value]{
+		Name:       strings.TrimSpace(record.Name),
+		Type:       search.EntityBusiness,
+		Source:     search.SourceUNCSL,
+		SourceID:   cmp.Or(strings.TrimSpace(record.ReferenceNumber), record.DataID),
+		SourceData: record,
+	}
+
+	out.Business = &search.Business{
+		Name:     out.Name,
+		AltNames: mapAliases(record.Aliases, ""),
+	}
+
+	out.Addresses = mapAddresses(record.Addresses)
+	out.SanctionsInfo = mapSanctionsInfo(record.UNListType, record.Comments)
+	out.HistoricalInfo = mapHistoricalInfo(record.ListedOn, record.LastDayUpdated)
+
+	return out
+}
+
+func joinNames(names ...string) string {
+	var parts []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func mapAliases(aliases []Alias, originalScript string) []string {
+	var out []string
+	for _, alias := range aliases {
+		if name := strings.TrimSpace(alias.Name); name != "" {
+			out = append(out, name)
+		}
+	}
+	if originalScript = strings.TrimSpace(originalScript); originalScript != "" {
+		out = append(out, originalScript)
+	}
+	return out
+}
+
+func mapGender(value string) search.Gender {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "male":
+		return search.GenderMale
+	case "female":
+		return search.GenderFemale
+	}
+	return search.GenderUnknown
+}
+
+var (
+	dateFormats = []string{"2006-01-02", "2006-01", "2006"}
+)
+
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, format := range dateFormats {
+		tt, err := time.Parse(format, value)
+		if err == nil {
+			return &tt
+		}
+	}
+	return nil
+}
+
+// mapBirthDate returns the first usable date of birth. Ranges (BETWEEN) use the starting year,
+// the full range remains available in SourceData.
+func mapBirthDate(dates []DateOfBirth) *time.Time {
+	for _, dob := range dates {
+		var value string
+		switch strings.ToUpper(strings.TrimSpace(dob.TypeOfDate)) {
+		case "BETWEEN":
+			value = dob.FromYear
+		default:
+			value = cmp.Or(dob.Date, dob.Year, dob.FromYear)
+		}
+		if tt := parseDate(value); tt != nil {
+			return tt
+		}
+	}
+	return nil
+}
+
+func mapGovernmentIDs(documents []Document) []search.GovernmentID {
+	var out []search.GovernmentID
+	for _, doc := range documents {
+		number := strings.TrimSpace(doc.Number)
+		if number == "" {
+			continue
+		}
+		out = append(out, search.GovernmentID{
+			Type:       mapGovernmentIDType(cmp.Or(doc.TypeOfDocument, doc.TypeOfDocument2)),
+			Country:    cmp.Or(doc.IssuingCountry, doc.CountryOfIssue),
+			Identifier: number,
+		})
+	}
+	return out
+}
+
+func mapGovernmentIDType(value string) search.GovernmentIDType {
+	value = strings.ToLower(value)
+	switch {
+	case strings.Contains(value, "diplomatic"):
+		return search.GovernmentIDDiplomaticPass
+	case strings.Contains(value, "passport"):
+		return search.GovernmentIDPassport
+	case strings.Contains(value, "national identification"):
+		return search.GovernmentIDNational
+	case strings.Contains(value, "tax"):
+		return search.GovernmentIDTax
+	case strings.Contains(value, "driving"), strings.Contains(value, "driver"):
+		return search.GovernmentIDDriversLicense
+	case strings.Contains(value, "birth"):
+		return search.GovernmentIDBirthCert
+	}
+	return search.GovernmentIDPersonalID
+}
+
+func mapAddresses(addrs []Address) []search.Address {
+	var out []search.Address
+	for _, addr := range addrs {
+		address := search.Address{
+			Line1:      strings.TrimSpace(addr.Street),
+			City:       strings.TrimSpace(addr.City),
+			State:      strings.TrimSpace(addr.StateProvince),
+			PostalCode: strings.TrimSpace(addr.ZipCode),
+			Country:    strings.TrimSpace(addr.Country),
+		}
+		if address == (search.Address{}) {
+			continue
+		}
+		out = append(out, address)
+	}
+	return out
+}
+
+func mapSanctionsInfo(listType, comments string) *search.SanctionsInfo {
+	listType = strings.TrimSpace(listType)
+	comments = strings.TrimSpace(comments)
+	if listType == "" && comments == "" {
+		return nil
+	}
+
+	info := &search.SanctionsInfo{
+		Description: comments,
+	}
+	if listType != "" {
+		info.Programs = []string{listType}
+	}
+	return info
+}
+
+func mapHistoricalInfo(listedOn string, lastUpdated []string) []search.HistoricalInfo {
+	var out []search.HistoricalInfo
+	add := func(infoType, value string) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return
+		}
+		info := search.HistoricalInfo{
+			Type:  infoType,
+			Value: value,
+		}
+		if tt := parseDate(value); tt != nil {
+			info.Date = *tt
+		}
+		out = append(out, info)
+	}
+
+	add("Listed", listedOn)
+	for _, value := range lastUpdated {
+		add("Last Updated", value)
+	}
+	return out
+}
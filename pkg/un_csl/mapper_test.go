@@ -0,0 +1,104 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moov-io/watchman/pkg/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readTestList(t *testing.T) *ConsolidatedList {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("testdata", "consolidated.xml"))
+	require.NoError(t, err)
+
+	list, err := ReadFile(fd)
+	require.NoError(t, err)
+
+	return list
+}
+
+func TestConvertSanctionsData(t *testing.T) {
+	entities := ConvertSanctionsData(readTestList(t))
+	require.Len(t, entities, 5)
+
+	for _, entity := range entities {
+		require.Equal(t, search.SourceUNCSL, entity.Source)
+		require.NotEmpty(t, entity.SourceID)
+	}
+
+	require.Nil(t, ConvertSanctionsData(nil))
+}
+
+func TestIndividualToEntity(t *testing.T) {
+	list := readTestList(t)
+
+	t.Run("exact DOB", func(t *testing.T) {
+		entity := IndividualToEntity(list.Individuals[0])
+		require.Equal(t, "RI WON HO", entity.Name)
+		require.Equal(t, search.EntityPerson, entity.Type)
+		require.Equal(t, "KPi.033", entity.SourceID)
+
+		require.NotNil(t, entity.Person)
+		require.Equal(t, search.GenderMale, entity.Person.Gender)
+		require.Equal(t, []string{"리원호"}, entity.Person.AltNames)
+		require.Equal(t, "1964-07-17", entity.Person.BirthDate.Format(time.DateOnly))
+
+		expectedIDs := []search.GovernmentID{
+			{Type: search.GovernmentIDPassport, Country: "Democratic People's Republic of Korea", Identifier: "381310014"},
+		}
+		require.Equal(t, expectedIDs, entity.Person.GovernmentIDs)
+
+		require.Equal(t, []search.Address{{Country: "Syrian Arab Republic"}}, entity.Addresses)
+
+		require.NotNil(t, entity.SanctionsInfo)
+		require.Equal(t, []string{"DPRK"}, entity.SanctionsInfo.Programs)
+
+		require.Len(t, entity.HistoricalInfo, 2)
+		require.Equal(t, "Listed", entity.HistoricalInfo[0].Type)
+		require.Equal(t, "2016-11-30", entity.HistoricalInfo[0].Date.Format(time.DateOnly))
+	})
+
+	t.Run("DOB range", func(t *testing.T) {
+		entity := IndividualToEntity(list.Individuals[1])
+		require.Equal(t, "ABDUL GHANI BARADAR", entity.Name)
+		require.Equal(t, []string{"Mullah Baradar Akhund", "Abdul Ghani Baradar Abdul Ahmad Turk"}, entity.Person.AltNames)
+		require.Equal(t, "1967-01-01", entity.Person.BirthDate.Format(time.DateOnly))
+		require.Equal(t, []string{"Mullah", "Deputy Minister of Defence under the Taliban regime"}, entity.Person.Titles)
+		require.Equal(t, search.GovernmentIDNational, entity.Person.GovernmentIDs[0].Type)
+	})
+
+	t.Run("approximate DOB", func(t *testing.T) {
+		entity := IndividualToEntity(list.Individuals[2])
+		require.Equal(t, "1975-01-01", entity.Person.BirthDate.Format(time.DateOnly))
+		require.Empty(t, entity.Person.GovernmentIDs)
+		require.Empty(t, entity.SanctionsInfo.Description)
+	})
+}
+
+func TestEntityToEntity(t *testing.T) {
+	list := readTestList(t)
+
+	entity := EntityToEntity(list.Entities[0])
+	require.Equal(t, "KOREA MINING DEVELOPMENT TRADING CORPORATION (KOMID)", entity.Name)
+	require.Equal(t, search.EntityBusiness, entity.Type)
+	require.Equal(t, "KPe.001", entity.SourceID)
+
+	require.NotNil(t, entity.Business)
+	require.Equal(t, []string{"CHANGGWANG SINYONG CORPORATION", "EXTERNAL TECHNOLOGY GENERAL CORPORATION"}, entity.Business.AltNames)
+
+	expected := []search.Address{
+		{Line1: "Central District", City: "Pyongyang", Country: "Democratic People's Republic of Korea"},
+	}
+	require.Equal(t, expected, entity.Addresses)
+	require.Contains(t, entity.SanctionsInfo.Description, "Primary arms dealer")
+}
@@ -0,0 +1,29 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+func ReadFile(fd io.ReadCloser) (*ConsolidatedList, error) {
+	if fd == nil {
+		return nil, errors.New("UN CSL file is empty or missing")
+	}
+	defer fd.Close()
+
+	return Parse(fd)
+}
+
+func Parse(r io.Reader) (*ConsolidatedList, error) {
+	var list ConsolidatedList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding UN CSL: %w", err)
+	}
+	return &list, nil
+}
@@ -0,0 +1,54 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	fd, err := os.Open(filepath.Join("testdata", "consolidated.xml"))
+	require.NoError(t, err)
+
+	list, err := ReadFile(fd)
+	require.NoError(t, err)
+	require.NotNil(t, list)
+
+	require.Equal(t, "2024-11-05T16:01:03.453-05:00", list.DateGenerated)
+	require.Len(t, list.Individuals, 3)
+	require.Len(t, list.Entities, 2)
+
+	ri := list.Individuals[0]
+	require.Equal(t, "KPi.033", ri.ReferenceNumber)
+	require.Equal(t, "RI", ri.FirstName)
+	require.Equal(t, "WON HO", ri.SecondName)
+	require.Equal(t, []string{"2017-09-11"}, ri.LastDayUpdated)
+	require.Len(t, ri.Documents, 1)
+	require.Equal(t, "381310014", ri.Documents[0].Number)
+
+	baradar := list.Individuals[1]
+	require.Len(t, baradar.Aliases, 2)
+	require.Equal(t, "Low", baradar.Aliases[1].Quality)
+	require.Equal(t, []DateOfBirth{{TypeOfDate: "BETWEEN", FromYear: "1967", ToYear: "1968"}}, baradar.DatesOfBirth)
+	require.Equal(t, "Uruzgan Province", baradar.PlacesOfBirth[0].StateProvince)
+
+	komid := list.Entities[0]
+	require.Equal(t, "KPe.001", komid.ReferenceNumber)
+	require.Len(t, komid.Aliases, 2)
+	require.Equal(t, "Pyongyang", komid.Addresses[0].City)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("<CONSOLIDATED_LIST><INDIVIDUALS>"))
+	require.Error(t, err)
+
+	_, err = ReadFile(nil)
+	require.Error(t, err)
+}
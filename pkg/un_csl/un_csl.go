@@ -0,0 +1,109 @@
+// Copyright The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package un_csl
+
+import (
+	"encoding/xml"
+)
+
+// ConsolidatedList is the United Nations Security Council Consolidated List
+//
+// See: https://main.un.org/securitycouncil/en/content/un-sc-consolidated-list
+type ConsolidatedList struct {
+	XMLName       xml.Name `xml:"CONSOLIDATED_LIST"`
+	DateGenerated string   `xml:"dateGenerated,attr"`
+
+	Individuals []Individual `xml:"INDIVIDUALS>INDIVIDUAL"`
+	Entities    []Entity     `xml:"ENTITIES>ENTITY"`
+}
+
+type Individual struct {
+	DataID     string `xml:"DATAID"`
+	VersionNum string `xml:"VERSIONNUM"`
+
+	FirstName          string `xml:"FIRST_NAME"`
+	SecondName         string `xml:"SECOND_NAME"`
+	ThirdName          string `xml:"THIRD_NAME"`
+	FourthName         string `xml:"FOURTH_NAME"`
+	NameOriginalScript string `xml:"NAME_ORIGINAL_SCRIPT"`
+
+	UNListType      string `xml:"UN_LIST_TYPE"`
+	ReferenceNumber string `xml:"REFERENCE_NUMBER"`
+	ListedOn        string `xml:"LISTED_ON"`
+	Gender          string `xml:"GENDER"`
+	Comments        string `xml:"COMMENTS1"`
+
+	Titles         []string `xml:"TITLE>VALUE"`
+	Designations   []string `xml:"DESIGNATION>VALUE"`
+	Nationalities  []string `xml:"NATIONALITY>VALUE"`
+	LastDayUpdated []string `xml:"LAST_DAY_UPDATED>VALUE"`
+
+	Aliases       []Alias        `xml:"INDIVIDUAL_ALIAS"`
+	Addresses     []Address      `xml:"INDIVIDUAL_ADDRESS"`
+	DatesOfBirth  []DateOfBirth  `xml:"INDIVIDUAL_DATE_OF_BIRTH"`
+	PlacesOfBirth []PlaceOfBirth `xml:"INDIVIDUAL_PLACE_OF_BIRTH"`
+	Documents     []Document     `xml:"INDIVIDUAL_DOCUMENT"`
+}
+
+type Entity struct {
+	DataID     string `xml:"DATAID"`
+	VersionNum string `xml:"VERSIONNUM"`
+
+	Name string `xml:"FIRST_NAME"`
+
+	UNListType      string `xml:"UN_LIST_TYPE"`
+	ReferenceNumber string `xml:"REFERENCE_NUMBER"`
+	ListedOn        string `xml:"LISTED_ON"`
+	Comments        string `xml:"COMMENTS1"`
+
+	LastDayUpdated []string `xml:"LAST_DAY_UPDATED>VALUE"`
+
+	Aliases   []Alias   `xml:"ENTITY_ALIAS"`
+	Addresses []Address `xml:"ENTITY_ADDRESS"`
+}
+
+type Alias struct {
+	// Quality is how reliable the alias is, "Good" or "Low"
+	Quality string `xml:"QUALITY"`
+	Name    string `xml:"ALIAS_NAME"`
+	Note    string `xml:"NOTE"`
+}
+
+type Address struct {
+	Street        string `xml:"STREET"`
+	City          string `xml:"CITY"`
+	StateProvince string `xml:"STATE_PROVINCE"`
+	ZipCode       string `xml:"ZIP_CODE"`
+	Country       string `xml:"COUNTRY"`
+	Note          string `xml:"NOTE"`
+}
+
+// DateOfBirth is either an exact date, a year or a range of years.
+type DateOfBirth struct {
+	// TypeOfDate is EXACT, APPROXIMATELY or BETWEEN
+	TypeOfDate string `xml:"TYPE_OF_DATE"`
+	Date       string `xml:"DATE"`
+	Year       string `xml:"YEAR"`
+	FromYear   string `xml:"FROM_YEAR"`
+	ToYear     string `xml:"TO_YEAR"`
+	Note       string `xml:"NOTE"`
+}
+
+type PlaceOfBirth struct {
+	City          string `xml:"CITY"`
+	StateProvince string `xml:"STATE_PROVINCE"`
+	Country       string `xml:"COUNTRY"`
+}
+
+type Document struct {
+	TypeOfDocument  string `xml:"TYPE_OF_DOCUMENT"`
+	TypeOfDocument2 string `xml:"TYPE_OF_DOCUMENT2"`
+	Number          string `xml:"NUMBER"`
+	IssuingCountry  string `xml:"ISSUING_COUNTRY"`
+	DateOfIssue     string `xml:"DATE_OF_ISSUE"`
+	CityOfIssue     string `xml:"CITY_OF_ISSUE"`
+	CountryOfIssue  string `xml:"COUNTRY_OF_ISSUE"`
+	Note            string `xml:"NOTE"`
+}